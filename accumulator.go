@@ -1,5 +1,10 @@
 package sqb
 
+import (
+	"fmt"
+	"reflect"
+)
+
 // In order to properly assign the result, in a generic way without requiring the
 // developer to perform type checks. Developers should create a separate accumulator
 // object to accumulate results. See common_test.go for an example accumulator
@@ -10,6 +15,14 @@ type Accumulator[T any] interface {
 	GetResults() []T
 }
 
+// Accumulators whose receivers are shared between rows should implement ResettableAccumulator. Reset is
+// called before each row is scanned, and should return every receiver to its zero value, so that a row
+// never keeps values from the previous one, e.g. when a column is NULL or a slice is reused.
+type ResettableAccumulator[T any] interface {
+	Accumulator[T]
+	Reset()
+}
+
 func NewAccumulator[T any](receiver func(r *T) map[string]interface{}) Accumulator[T] {
 	r := new(T)
 	return &genericAccumulator[T]{
@@ -27,10 +40,17 @@ type genericAccumulator[T any] struct {
 	receiver *T
 }
 
+// The receiver is copied into the results. Reset runs before the next row is scanned, so the copy shares no
+// memory with later rows.
 func (r *genericAccumulator[T]) Acc() {
 	r.results = append(r.results, *r.receiver)
 }
 
+func (r *genericAccumulator[T]) Reset() {
+	var zero T
+	*r.receiver = zero
+}
+
 func (r *genericAccumulator[T]) GetColumnReceiverMap() map[string]interface{} {
 	return r.ColumnReceiverMap
 }
@@ -38,3 +58,31 @@ func (r *genericAccumulator[T]) GetColumnReceiverMap() map[string]interface{} {
 func (r *genericAccumulator[T]) GetResults() []T {
 	return r.results
 }
+
+// Create an accumulator whose receivers are the fields of T, mapped to columns in the same way as a table
+// model, including embedded and inline structs. Fields are scanned into directly, so nullable columns need
+// pointer fields or types which scan NULL themselves.
+func NewAutoAccumulator[T any](dialect Dialect, options ...TableOption) Accumulator[T] {
+	resultType := reflect.TypeOf((*T)(nil)).Elem()
+	if resultType.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("AutoAccumulator: result must be a struct type, got %s", resultType))
+	}
+
+	o := tableOptions{}
+	for _, option := range options {
+		option(&o)
+	}
+
+	columns := modelColumns(resultType, dialect, o)
+
+	return NewAccumulator(func(r *T) map[string]interface{} {
+		v := reflect.ValueOf(r).Elem()
+		receivers := make(map[string]interface{}, len(columns))
+
+		for _, column := range columns {
+			receivers[column.name] = v.FieldByIndex(column.index).Addr().Interface()
+		}
+
+		return receivers
+	})
+}
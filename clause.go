@@ -40,7 +40,98 @@ func (f *FilterClause) Build(params *ParamList) string {
 		return f.template
 	}
 
-	return fmt.Sprintf(f.template, params.RecordValueAndReturnParam(f.paramValue))
+	return fmt.Sprintf(f.template, params.AppendValueAndReturnParam(f.paramValue))
+}
+
+// A TemplateClause fills each %s in its template with a param, in order. Useful for predicates which need
+// more than one param.
+type TemplateClause struct {
+	template    string
+	paramValues []any
+}
+
+func NewTemplateClause(template string, paramValues ...any) *TemplateClause {
+	return &TemplateClause{
+		template:    template,
+		paramValues: paramValues,
+	}
+}
+
+func (c *TemplateClause) Build(params *ParamList) string {
+	built := make([]any, 0, len(c.paramValues))
+	for _, v := range c.paramValues {
+		built = append(built, params.AppendValueAndReturnParam(v))
+	}
+
+	return fmt.Sprintf(c.template, built...)
+}
+
+// A FeatureClause is only valid for dialects supporting feature. For other dialects, building records an
+// error instead.
+type FeatureClause struct {
+	feature Feature
+	clause  Clause
+}
+
+func NewFeatureClause(feature Feature, clause Clause) *FeatureClause {
+	return &FeatureClause{
+		feature: feature,
+		clause:  clause,
+	}
+}
+
+func (c *FeatureClause) Build(params *ParamList) string {
+	if !supports(params.dialect, c.feature) {
+		params.RecordError(fmt.Errorf("dialect %T does not support %s", params.dialect, c.feature))
+	}
+
+	return c.clause.Build(params)
+}
+
+// A SubqueryClause embeds a select statement within a filter. The subquery records its params in the same
+// ParamList as the enclosing query, so they are numbered consistently.
+type SubqueryClause struct {
+	// Must contain a single %s, which is replaced by the subquery
+	template string
+	subquery Selectable
+}
+
+func NewSubqueryClause(template string, subquery Selectable) *SubqueryClause {
+	return &SubqueryClause{
+		template: template,
+		subquery: subquery,
+	}
+}
+
+func (s *SubqueryClause) Build(params *ParamList) string {
+	return fmt.Sprintf(s.template, s.subquery.BuildSelect(params))
+}
+
+// A JoinClause joins a source to a table on the equality of two qualified columns
+type JoinClause struct {
+	template string
+
+	// Conditions the joined source adds to the join, e.g. its scopes
+	sourceFilters func(params *ParamList) string
+}
+
+func NewJoinClause(joinType string, source string, column string, sourceColumn string) *JoinClause {
+	return &JoinClause{
+		template: fmt.Sprintf(" %s %s ON %s = %s", joinType, source, column, sourceColumn),
+	}
+}
+
+func (j *JoinClause) Build(params *ParamList) string {
+	if j.sourceFilters == nil {
+		return j.template
+	}
+
+	filters := j.sourceFilters(params)
+	if filters == "" {
+		return j.template
+	}
+
+	return j.template + " AND " + filters
 }
 
 // A CompoundClause is necessary to effectively combine clauses
@@ -105,25 +196,38 @@ func getOrderByValue(sd SortDirection) string {
 }
 
 type OrderByClause struct {
-	template      string
 	columnName    string
 	sortDirection SortDirection
 }
 
 func NewOrderByClause(columnName string, sortDirection SortDirection) *OrderByClause {
 	return &OrderByClause{
-		template:      "ORDER BY %s %s",
 		columnName:    columnName,
-		sortDirection: Ascending,
+		sortDirection: sortDirection,
 	}
 }
 
+// Builds a single ordering term, e.g. name DESC. Use BuildOrderBy to combine terms into a clause.
 func (o *OrderByClause) Build(params *ParamList) string {
-	if o.columnName == "" {
+	if o.sortDirection == Unset {
+		return o.columnName
+	}
+
+	return fmt.Sprint(o.columnName, " ", getOrderByValue(o.sortDirection))
+}
+
+// Combine ordering terms into an ORDER BY clause
+func BuildOrderBy(orderBy []*OrderByClause, params *ParamList) string {
+	if len(orderBy) == 0 {
 		return ""
 	}
 
-	return fmt.Sprintf(o.template, params.RecordValueAndReturnParam(o.columnName), getOrderByValue(o.sortDirection))
+	terms := make([]string, 0, len(orderBy))
+	for _, o := range orderBy {
+		terms = append(terms, o.Build(params))
+	}
+
+	return fmt.Sprint(" ORDER BY ", strings.Join(terms, ", "))
 }
 
 type LimitClause struct {
@@ -138,11 +242,18 @@ func NewLimitClause(rowCount int64, offset int64) *LimitClause {
 	}
 }
 
-func (o *LimitClause) Build() string {
-	offsetClause := ""
-	if o.offset > 0 {
-		offsetClause = fmt.Sprint(" OFFSET ", o.offset)
+// Limits are bound as params by default so that every page of a query shares the same SQL text, and
+// can reuse a prepared statement. The offset is always bound for the same reason, even when it is zero.
+// Dialects which do not support binding limits fall back to writing the values into the query.
+func (o *LimitClause) Build(params *ParamList) string {
+	if !bindsLimit(params.dialect) {
+		offsetClause := ""
+		if o.offset > 0 {
+			offsetClause = fmt.Sprint(" OFFSET ", o.offset)
+		}
+
+		return fmt.Sprint(" LIMIT ", o.rowCount, offsetClause)
 	}
 
-	return fmt.Sprint(" LIMIT ", o.rowCount, offsetClause)
+	return fmt.Sprint(" LIMIT ", params.AppendValueAndReturnParam(o.rowCount), " OFFSET ", params.AppendValueAndReturnParam(o.offset))
 }
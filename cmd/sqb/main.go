@@ -0,0 +1,117 @@
+/*
+sqb runs SQb tooling against a database. The migrate command applies migrations with the migrate package:
+
+	sqb migrate -database postgres://localhost/app -dir migrations up
+	sqb migrate -database postgres://localhost/app -dir migrations down -steps 2
+	sqb migrate -database postgres://localhost/app -dir migrations status
+
+The database URL defaults to $DATABASE_URL. Migrations run against postgres, through lib/pq.
+*/
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	_ "github.com/lib/pq"
+	sqb "github.com/themanciraptor/SQb"
+	"github.com/themanciraptor/SQb/migrate"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("sqb: ")
+
+	if err := run(context.Background(), os.Args[1:], os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(ctx context.Context, args []string, out io.Writer) error {
+	if len(args) == 0 || args[0] != "migrate" {
+		return fmt.Errorf("usage: sqb migrate [flags] up|down|status")
+	}
+
+	flags := flag.NewFlagSet("sqb migrate", flag.ContinueOnError)
+	database := flags.String("database", os.Getenv("DATABASE_URL"), "database URL, defaults to $DATABASE_URL")
+	dir := flags.String("dir", "migrations", "directory of migration files")
+	table := flags.String("table", "schema_migrations", "table recording applied migrations")
+
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	if flags.NArg() == 0 {
+		return fmt.Errorf("usage: sqb migrate [flags] up|down|status")
+	}
+
+	if *database == "" {
+		return fmt.Errorf("migrate: no database, set -database or $DATABASE_URL")
+	}
+
+	db, err := sql.Open("postgres", *database)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	m, err := migrate.New(db, sqb.Psql(), os.DirFS(*dir), migrate.WithTable(*table))
+	if err != nil {
+		return err
+	}
+
+	return runMigrate(ctx, m, flags.Args(), out)
+}
+
+func runMigrate(ctx context.Context, m *migrate.Migrator, args []string, out io.Writer) error {
+	switch args[0] {
+	case "up":
+		applied, err := m.Up(ctx)
+		for _, migration := range applied {
+			fmt.Fprintf(out, "applied %d %s\n", migration.Version, migration.Name)
+		}
+
+		return err
+	case "down":
+		flags := flag.NewFlagSet("sqb migrate down", flag.ContinueOnError)
+		steps := flags.Int("steps", 1, "number of migrations to roll back")
+
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+
+		if *steps < 1 {
+			return fmt.Errorf("migrate: -steps must be at least 1, got %d", *steps)
+		}
+
+		rolledBack, err := m.Down(ctx, *steps)
+		for _, migration := range rolledBack {
+			fmt.Fprintf(out, "rolled back %d %s\n", migration.Version, migration.Name)
+		}
+
+		return err
+	case "status":
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, status := range statuses {
+			state := "pending"
+			if status.Applied {
+				state = "applied " + status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+
+			fmt.Fprintf(out, "%d %s: %s\n", status.Version, status.Name, state)
+		}
+
+		return nil
+	default:
+		return fmt.Errorf("migrate: unknown command %s, expected up, down or status", args[0])
+	}
+}
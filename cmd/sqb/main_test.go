@@ -0,0 +1,30 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Run_Usage(t *testing.T) {
+	t.Setenv("DATABASE_URL", "")
+
+	testCases := []struct {
+		args     []string
+		expected string
+	}{
+		{args: []string{"migrations"}, expected: "usage: sqb migrate [flags] up|down|status"},
+		{args: []string{"migrate", "-dir", "x"}, expected: "usage: sqb migrate [flags] up|down|status"},
+		{args: []string{"migrate", "up"}, expected: "migrate: no database, set -database or $DATABASE_URL"},
+		{args: []string{"migrate", "-database", "postgres://localhost/app", "-dir", t.TempDir(), "sideways"}, expected: "migrate: unknown command sideways, expected up, down or status"},
+		{args: []string{"migrate", "-database", "postgres://localhost/app", "-dir", t.TempDir(), "down", "-steps", "-1"}, expected: "migrate: -steps must be at least 1, got -1"},
+	}
+
+	for _, tc := range testCases {
+		err := run(context.Background(), tc.args, &bytes.Buffer{})
+
+		assert.EqualError(t, err, tc.expected, tc.args)
+	}
+}
@@ -0,0 +1,422 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"path"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	sqb "github.com/themanciraptor/SQb"
+)
+
+const sqbImportPath = "github.com/themanciraptor/SQb"
+
+type generateConfig struct {
+	dir    string
+	types  []string
+	tag    string
+	snake  bool
+	output string
+}
+
+// A parsed package: its struct types, the methods declared on each type and the imports of each file
+type sourcePackage struct {
+	fset    *token.FileSet
+	name    string
+	structs map[string]*sourceStruct
+	methods map[string]map[string]bool
+}
+
+type sourceStruct struct {
+	typ     *ast.StructType
+	imports map[string]string
+}
+
+type model struct {
+	Name    string
+	Columns []modelColumn
+}
+
+type modelColumn struct {
+	Field string // Name of the column's ColumnRef, e.g. AddressCity
+	Path  string // Selector of the column's field from a row, e.g. Address.City
+	Name  string // Column name
+	Type  string // Go type of the column's field
+}
+
+// Generate the column references and accumulators for the configured types, returning the package name
+// and the formatted source
+func generate(config generateConfig) (string, []byte, error) {
+	pkg, err := parsePackage(config.dir, config.output)
+	if err != nil {
+		return "", nil, err
+	}
+
+	imports := map[string]string{}
+	models := make([]model, 0, len(config.types))
+
+	for _, typeName := range config.types {
+		typeName = strings.TrimSpace(typeName)
+
+		s, ok := pkg.structs[typeName]
+		if !ok {
+			return "", nil, fmt.Errorf("no struct type %s in package %s", typeName, pkg.name)
+		}
+
+		m := model{Name: typeName}
+		if m.Columns, err = pkg.columns(config, s, "", "", "", imports); err != nil {
+			return "", nil, fmt.Errorf("%s: %w", typeName, err)
+		}
+
+		if err := checkDuplicates(m.Columns); err != nil {
+			return "", nil, fmt.Errorf("%s: %w", typeName, err)
+		}
+
+		models = append(models, m)
+	}
+
+	var buf bytes.Buffer
+	if err := outputTemplate.Execute(&buf, map[string]interface{}{
+		"Package": pkg.name,
+		"Imports": sortedImports(imports),
+		"Models":  models,
+	}); err != nil {
+		return "", nil, err
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return "", nil, fmt.Errorf("formatting generated code: %w", err)
+	}
+
+	return pkg.name, src, nil
+}
+
+func parsePackage(dir string, output string) (*sourcePackage, error) {
+	fset := token.NewFileSet()
+	packages, err := parser.ParseDir(fset, dir, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	pkg := &sourcePackage{
+		fset:    fset,
+		structs: map[string]*sourceStruct{},
+		methods: map[string]map[string]bool{},
+	}
+
+	for name, p := range packages {
+		if strings.HasSuffix(name, "_test") {
+			continue
+		}
+
+		if pkg.name != "" {
+			return nil, fmt.Errorf("found packages %s and %s in %s", pkg.name, name, dir)
+		}
+
+		pkg.name = name
+
+		for fileName, file := range p.Files {
+			base := path.Base(fileName)
+			if base == output || strings.HasSuffix(base, "_test.go") {
+				continue
+			}
+
+			pkg.addFile(file)
+		}
+	}
+
+	if pkg.name == "" {
+		return nil, fmt.Errorf("no Go package found in %s", dir)
+	}
+
+	return pkg, nil
+}
+
+func (p *sourcePackage) addFile(file *ast.File) {
+	imports := map[string]string{}
+	for _, spec := range file.Imports {
+		importPath, _ := strconv.Unquote(spec.Path.Value)
+
+		name := path.Base(importPath)
+		if importPath == sqbImportPath {
+			name = "sqb"
+		}
+
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+
+		imports[name] = importPath
+	}
+
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				if typeSpec, ok := spec.(*ast.TypeSpec); ok {
+					if structType, ok := typeSpec.Type.(*ast.StructType); ok && typeSpec.TypeParams == nil {
+						p.structs[typeSpec.Name.Name] = &sourceStruct{typ: structType, imports: imports}
+					}
+				}
+			}
+		case *ast.FuncDecl:
+			if decl.Recv == nil || len(decl.Recv.List) == 0 {
+				continue
+			}
+
+			receiver := decl.Recv.List[0].Type
+			if star, ok := receiver.(*ast.StarExpr); ok {
+				receiver = star.X
+			}
+
+			if ident, ok := receiver.(*ast.Ident); ok {
+				if p.methods[ident.Name] == nil {
+					p.methods[ident.Name] = map[string]bool{}
+				}
+
+				p.methods[ident.Name][decl.Name.Name] = true
+			}
+		}
+	}
+}
+
+// Walk a struct's fields in the same way as sqb.NewTable, flattening embedded structs and structs tagged
+// inline
+func (p *sourcePackage) columns(config generateConfig, s *sourceStruct, fieldPrefix string, pathPrefix string, namePrefix string, imports map[string]string) ([]modelColumn, error) {
+	var columns []modelColumn
+
+	for _, field := range s.typ.Fields.List {
+		tag, tagged := "", false
+		if field.Tag != nil {
+			value, _ := strconv.Unquote(field.Tag.Value)
+			tag, tagged = reflect.StructTag(value).Lookup(config.tag)
+		}
+
+		if tag == "-" {
+			continue
+		}
+
+		name, options := sqb.ParseTag(tag)
+		_, inline := options["inline"]
+
+		if len(field.Names) == 0 {
+			typeName := embeddedTypeName(field.Type)
+
+			nested, ok := p.flattenable(field.Type)
+			if !ok && name == "" {
+				return nil, fmt.Errorf("cannot flatten embedded field %s, declare its type in this package or tag it with a column name", typeName)
+			}
+
+			if ok && (inline || name == "") {
+				nestedColumns, err := p.columns(config, nested, fieldPrefix, pathPrefix+typeName+".", namePrefix+name, imports)
+				if err != nil {
+					return nil, err
+				}
+
+				columns = append(columns, nestedColumns...)
+				continue
+			}
+
+			field.Names = []*ast.Ident{ast.NewIdent(typeName)}
+		}
+
+		for _, fieldName := range field.Names {
+			if inline {
+				nested, ok := p.flattenable(field.Type)
+				if !ok {
+					return nil, fmt.Errorf("cannot inline field %s, its type must be a struct declared in this package", fieldName.Name)
+				}
+
+				nestedColumns, err := p.columns(config, nested, fieldPrefix+fieldName.Name, pathPrefix+fieldName.Name+".", namePrefix+name, imports)
+				if err != nil {
+					return nil, err
+				}
+
+				columns = append(columns, nestedColumns...)
+				continue
+			}
+
+			if !tagged && (!config.snake || !fieldName.IsExported()) {
+				continue
+			}
+
+			columnName := name
+			if columnName == "" {
+				if !config.snake {
+					return nil, fmt.Errorf("field %s has no column name", fieldName.Name)
+				}
+
+				columnName = sqb.SnakeCase(fieldName.Name)
+			}
+
+			typ, err := p.typeString(field.Type, s.imports, imports)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %w", fieldName.Name, err)
+			}
+
+			columns = append(columns, modelColumn{
+				Field: fieldPrefix + fieldName.Name,
+				Path:  pathPrefix + fieldName.Name,
+				Name:  namePrefix + columnName,
+				Type:  typ,
+			})
+		}
+	}
+
+	return columns, nil
+}
+
+// Structs declared in the package are flattened unless they scan themselves
+func (p *sourcePackage) flattenable(expr ast.Expr) (*sourceStruct, bool) {
+	ident, ok := expr.(*ast.Ident)
+	if !ok {
+		return nil, false
+	}
+
+	s, ok := p.structs[ident.Name]
+	if !ok || p.methods[ident.Name]["Scan"] {
+		return nil, false
+	}
+
+	return s, true
+}
+
+// Print a field's type, recording the imports it needs
+func (p *sourcePackage) typeString(expr ast.Expr, fileImports map[string]string, imports map[string]string) (string, error) {
+	var err error
+
+	ast.Inspect(expr, func(n ast.Node) bool {
+		selector, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+
+		if ident, ok := selector.X.(*ast.Ident); ok {
+			importPath, ok := fileImports[ident.Name]
+			if !ok {
+				err = fmt.Errorf("unknown package %s", ident.Name)
+				return false
+			}
+
+			imports[ident.Name] = importPath
+		}
+
+		return false
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, p.fset, expr); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+func embeddedTypeName(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.StarExpr:
+		return embeddedTypeName(expr.X)
+	case *ast.SelectorExpr:
+		return expr.Sel.Name
+	case *ast.Ident:
+		return expr.Name
+	}
+
+	return fmt.Sprint(expr)
+}
+
+func checkDuplicates(columns []modelColumn) error {
+	seen := map[string]string{}
+
+	for _, column := range columns {
+		if other, ok := seen[column.Name]; ok {
+			return fmt.Errorf("fields %s and %s are both named %s", other, column.Path, column.Name)
+		}
+
+		seen[column.Name] = column.Path
+	}
+
+	return nil
+}
+
+type importSpec struct {
+	Name string
+	Path string
+}
+
+// The generated file always imports sqb, other imports are sorted by path
+func sortedImports(imports map[string]string) []importSpec {
+	specs := []importSpec{}
+
+	for name, importPath := range imports {
+		if importPath == sqbImportPath {
+			continue
+		}
+
+		if path.Base(importPath) == name {
+			name = ""
+		}
+
+		specs = append(specs, importSpec{Name: name, Path: importPath})
+	}
+
+	sort.Slice(specs, func(i, j int) bool { return specs[i].Path < specs[j].Path })
+
+	return specs
+}
+
+var outputTemplate = template.Must(template.New("output").Parse(`// Code generated by sqbgen; DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+{{- range .Imports}}
+	{{.Name}} "{{.Path}}"
+{{- end}}
+
+	sqb "github.com/themanciraptor/SQb"
+)
+{{range .Models}}{{$model := .Name}}
+// Column names of {{$model}}
+const (
+{{- range .Columns}}
+	{{$model}}Column{{.Field}} = "{{.Name}}"
+{{- end}}
+)
+
+// Typed references to the columns of {{$model}}
+var {{$model}}Cols = struct {
+{{- range .Columns}}
+	{{.Field}} sqb.ColumnRef[{{.Type}}]
+{{- end}}
+}{
+{{- range .Columns}}
+	{{.Field}}: sqb.NewColumnRef[{{.Type}}]({{$model}}Column{{.Field}}),
+{{- end}}
+}
+
+// Create an accumulator which scans each row into {{$model}}
+func New{{$model}}Accumulator() sqb.Accumulator[{{$model}}] {
+	return sqb.NewAccumulator(func(r *{{$model}}) map[string]interface{} {
+		return map[string]interface{}{
+{{- range .Columns}}
+			{{$model}}Column{{.Field}}: &r.{{.Path}},
+{{- end}}
+		}
+	})
+}
+{{end}}`))
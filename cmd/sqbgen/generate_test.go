@@ -0,0 +1,86 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Generate_MatchesExample(t *testing.T) {
+	expected, err := os.ReadFile("internal/example/models_sqb.go")
+	assert.NoError(t, err)
+
+	_, src, err := generate(generateConfig{
+		dir:    "internal/example",
+		types:  []string{"User", "Order", "OrderSummary"},
+		tag:    "psql",
+		output: "models_sqb.go",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, string(expected), string(src), "run go generate ./cmd/sqbgen/... to update the example")
+}
+
+func Test_Generate_NamesUntaggedFields(t *testing.T) {
+	dir := writePackage(t, `package models
+
+type Account struct {
+	AccountID int64
+	Name      string `+"`psql:\"display_name\"`"+`
+	internal  string
+}
+`)
+
+	_, src, err := generate(generateConfig{dir: dir, types: []string{"Account"}, tag: "psql", snake: true})
+
+	assert.NoError(t, err)
+	assert.Contains(t, string(src), `AccountColumnAccountID = "account_id"`)
+	assert.Contains(t, string(src), `AccountColumnName      = "display_name"`)
+	assert.NotContains(t, string(src), "internal")
+}
+
+func Test_Generate_Errors(t *testing.T) {
+	testCases := map[string]struct {
+		source   string
+		typeName string
+		expected string
+	}{
+		"missing type": {
+			source:   "package models\n",
+			typeName: "Account",
+			expected: "no struct type Account in package models",
+		},
+		"missing column name": {
+			source:   "package models\n\ntype Account struct {\n\tID int64 `psql:\",pk\"`\n}\n",
+			typeName: "Account",
+			expected: "Account: field ID has no column name",
+		},
+		"duplicate column names": {
+			source:   "package models\n\ntype Account struct {\n\tID int64 `psql:\"id\"`\n\tOther int64 `psql:\"id\"`\n}\n",
+			typeName: "Account",
+			expected: "Account: fields ID and Other are both named id",
+		},
+		"embedded type from another package": {
+			source:   "package models\n\nimport \"time\"\n\ntype Account struct {\n\ttime.Time\n}\n",
+			typeName: "Account",
+			expected: "Account: cannot flatten embedded field Time, declare its type in this package or tag it with a column name",
+		},
+	}
+
+	for description, tc := range testCases {
+		t.Run(description, func(t *testing.T) {
+			_, _, err := generate(generateConfig{dir: writePackage(t, tc.source), types: []string{tc.typeName}, tag: "psql"})
+
+			assert.EqualError(t, err, tc.expected)
+		})
+	}
+}
+
+func writePackage(t *testing.T, source string) string {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "models.go"), []byte(source), 0o644))
+
+	return dir
+}
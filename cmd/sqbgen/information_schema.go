@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+// A row of information_schema.columns, as exported by e.g.
+//
+//	SELECT json_agg(c) FROM information_schema.columns c WHERE table_schema = 'public'
+//
+// information_schema.columns does not record primary keys, an export may add a primary_key field for them.
+type informationSchemaColumn struct {
+	TableSchema     string  `json:"table_schema"`
+	TableName       string  `json:"table_name"`
+	ColumnName      string  `json:"column_name"`
+	OrdinalPosition int     `json:"ordinal_position"`
+	DataType        string  `json:"data_type"`
+	UDTName         string  `json:"udt_name"`
+	IsNullable      string  `json:"is_nullable"`
+	ColumnDefault   *string `json:"column_default"`
+	IsIdentity      string  `json:"is_identity"`
+	IdentityGen     string  `json:"identity_generation"`
+	IsGenerated     string  `json:"is_generated"`
+	PrimaryKey      bool    `json:"primary_key"`
+}
+
+func parseInformationSchema(data []byte) ([]*schemaTable, error) {
+	var rows []informationSchemaColumn
+	if err := json.Unmarshal(data, &rows); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].OrdinalPosition < rows[j].OrdinalPosition })
+
+	tables := map[string]*schemaTable{}
+	var order []*schemaTable
+
+	for _, row := range rows {
+		key := row.TableSchema + "." + row.TableName
+
+		table, ok := tables[key]
+		if !ok {
+			table = &schemaTable{schema: row.TableSchema, name: row.TableName}
+			tables[key] = table
+			order = append(order, table)
+		}
+
+		column := &schemaColumn{
+			name:       row.ColumnName,
+			dataType:   strings.ToLower(row.DataType),
+			nullable:   row.IsNullable == "YES",
+			primaryKey: row.PrimaryKey,
+			generated:  row.IsGenerated == "ALWAYS" || row.IdentityGen == "ALWAYS",
+			hasDefault: row.ColumnDefault != nil || row.IsIdentity == "YES",
+		}
+
+		if row.ColumnDefault != nil {
+			column.defaultValue = *row.ColumnDefault
+		}
+
+		// Arrays are reported as ARRAY, with the element type named by udt_name, e.g. _int4
+		if column.dataType == "array" {
+			column.array = true
+			column.dataType = strings.TrimPrefix(row.UDTName, "_")
+		}
+
+		table.columns = append(table.columns, column)
+	}
+
+	return order, nil
+}
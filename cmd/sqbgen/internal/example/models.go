@@ -0,0 +1,43 @@
+// Package example holds models for testing sqbgen. models_sqb.go is generated from them.
+package example
+
+import (
+	"time"
+
+	sqb "github.com/themanciraptor/SQb"
+)
+
+//go:generate go run ../.. -type User,Order,OrderSummary -output models_sqb.go
+
+type Timestamps struct {
+	CreatedAt time.Time `psql:"created_at,readonly,default=now()"`
+	UpdatedAt time.Time `psql:"updated_at"`
+}
+
+type Address struct {
+	Street string `psql:"street"`
+	City   string `psql:"city"`
+}
+
+type User struct {
+	ID int64 `psql:"id,pk,default"`
+	Timestamps
+	Email    string           `psql:"email"`
+	Nickname sqb.Null[string] `psql:"nickname"`
+	Address  Address          `psql:"addr_,inline"`
+	Secret   string           `psql:"-"`
+}
+
+type Order struct {
+	ID     int64    `psql:"id,pk"`
+	UserID int64    `psql:"user_id"`
+	Status string   `psql:"status,enum=open|paid|shipped"`
+	Tags   []string `psql:"tags"`
+}
+
+// A result type for queries joining users and orders
+type OrderSummary struct {
+	UserID int64  `psql:"user_id"`
+	Email  string `psql:"email"`
+	Orders int64  `psql:"orders"`
+}
@@ -0,0 +1,116 @@
+// Code generated by sqbgen; DO NOT EDIT.
+
+package example
+
+import (
+	"time"
+
+	sqb "github.com/themanciraptor/SQb"
+)
+
+// Column names of User
+const (
+	UserColumnID            = "id"
+	UserColumnCreatedAt     = "created_at"
+	UserColumnUpdatedAt     = "updated_at"
+	UserColumnEmail         = "email"
+	UserColumnNickname      = "nickname"
+	UserColumnAddressStreet = "addr_street"
+	UserColumnAddressCity   = "addr_city"
+)
+
+// Typed references to the columns of User
+var UserCols = struct {
+	ID            sqb.ColumnRef[int64]
+	CreatedAt     sqb.ColumnRef[time.Time]
+	UpdatedAt     sqb.ColumnRef[time.Time]
+	Email         sqb.ColumnRef[string]
+	Nickname      sqb.ColumnRef[sqb.Null[string]]
+	AddressStreet sqb.ColumnRef[string]
+	AddressCity   sqb.ColumnRef[string]
+}{
+	ID:            sqb.NewColumnRef[int64](UserColumnID),
+	CreatedAt:     sqb.NewColumnRef[time.Time](UserColumnCreatedAt),
+	UpdatedAt:     sqb.NewColumnRef[time.Time](UserColumnUpdatedAt),
+	Email:         sqb.NewColumnRef[string](UserColumnEmail),
+	Nickname:      sqb.NewColumnRef[sqb.Null[string]](UserColumnNickname),
+	AddressStreet: sqb.NewColumnRef[string](UserColumnAddressStreet),
+	AddressCity:   sqb.NewColumnRef[string](UserColumnAddressCity),
+}
+
+// Create an accumulator which scans each row into User
+func NewUserAccumulator() sqb.Accumulator[User] {
+	return sqb.NewAccumulator(func(r *User) map[string]interface{} {
+		return map[string]interface{}{
+			UserColumnID:            &r.ID,
+			UserColumnCreatedAt:     &r.Timestamps.CreatedAt,
+			UserColumnUpdatedAt:     &r.Timestamps.UpdatedAt,
+			UserColumnEmail:         &r.Email,
+			UserColumnNickname:      &r.Nickname,
+			UserColumnAddressStreet: &r.Address.Street,
+			UserColumnAddressCity:   &r.Address.City,
+		}
+	})
+}
+
+// Column names of Order
+const (
+	OrderColumnID     = "id"
+	OrderColumnUserID = "user_id"
+	OrderColumnStatus = "status"
+	OrderColumnTags   = "tags"
+)
+
+// Typed references to the columns of Order
+var OrderCols = struct {
+	ID     sqb.ColumnRef[int64]
+	UserID sqb.ColumnRef[int64]
+	Status sqb.ColumnRef[string]
+	Tags   sqb.ColumnRef[[]string]
+}{
+	ID:     sqb.NewColumnRef[int64](OrderColumnID),
+	UserID: sqb.NewColumnRef[int64](OrderColumnUserID),
+	Status: sqb.NewColumnRef[string](OrderColumnStatus),
+	Tags:   sqb.NewColumnRef[[]string](OrderColumnTags),
+}
+
+// Create an accumulator which scans each row into Order
+func NewOrderAccumulator() sqb.Accumulator[Order] {
+	return sqb.NewAccumulator(func(r *Order) map[string]interface{} {
+		return map[string]interface{}{
+			OrderColumnID:     &r.ID,
+			OrderColumnUserID: &r.UserID,
+			OrderColumnStatus: &r.Status,
+			OrderColumnTags:   &r.Tags,
+		}
+	})
+}
+
+// Column names of OrderSummary
+const (
+	OrderSummaryColumnUserID = "user_id"
+	OrderSummaryColumnEmail  = "email"
+	OrderSummaryColumnOrders = "orders"
+)
+
+// Typed references to the columns of OrderSummary
+var OrderSummaryCols = struct {
+	UserID sqb.ColumnRef[int64]
+	Email  sqb.ColumnRef[string]
+	Orders sqb.ColumnRef[int64]
+}{
+	UserID: sqb.NewColumnRef[int64](OrderSummaryColumnUserID),
+	Email:  sqb.NewColumnRef[string](OrderSummaryColumnEmail),
+	Orders: sqb.NewColumnRef[int64](OrderSummaryColumnOrders),
+}
+
+// Create an accumulator which scans each row into OrderSummary
+func NewOrderSummaryAccumulator() sqb.Accumulator[OrderSummary] {
+	return sqb.NewAccumulator(func(r *OrderSummary) map[string]interface{} {
+		return map[string]interface{}{
+			OrderSummaryColumnUserID: &r.UserID,
+			OrderSummaryColumnEmail:  &r.Email,
+			OrderSummaryColumnOrders: &r.Orders,
+		}
+	})
+}
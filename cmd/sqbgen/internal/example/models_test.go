@@ -0,0 +1,34 @@
+package example
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	sqb "github.com/themanciraptor/SQb"
+)
+
+func Test_GeneratedColumns_BuildQueries(t *testing.T) {
+	since := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	acc := NewUserAccumulator()
+
+	q := sqb.NewTable[User]("users", sqb.Psql(), &User{}).
+		Select(UserColumnID, UserColumnEmail, UserColumnAddressCity).
+		Where(UserCols.CreatedAt.Gt(since), UserCols.AddressCity.In("Paris", "Oslo")).
+		LoadReceiversFromAccumulator(acc).
+		Build(acc, sqb.Psql())
+
+	assert.Equal(t, "SELECT id, email, addr_city FROM users WHERE (created_at > $1 AND addr_city IN ($2, $3))", q.GetQuery())
+	assert.Equal(t, []interface{}{since, "Paris", "Oslo"}, q.GetParams())
+}
+
+func Test_GeneratedColumns_CheckEnumValues(t *testing.T) {
+	acc := NewOrderAccumulator()
+
+	_, err := sqb.NewTable[Order]("orders", sqb.Psql(), &Order{}).
+		Where(OrderCols.Status.Eq("lost")).
+		LoadReceiversFromAccumulator(acc).
+		TryBuild(acc, sqb.Psql())
+
+	assert.EqualError(t, err, "Build: column status: value \"lost\" is not one of open|paid|shipped")
+}
@@ -0,0 +1,292 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// A table read from a schema dump
+type schemaTable struct {
+	schema  string
+	name    string
+	columns []*schemaColumn
+}
+
+type schemaColumn struct {
+	name     string
+	dataType string // Postgres type without modifiers, e.g. character varying, or the element type of an array
+	array    bool
+	nullable bool
+
+	primaryKey   bool
+	generated    bool
+	hasDefault   bool
+	defaultValue string
+	enum         []string
+}
+
+func (t *schemaTable) column(name string) *schemaColumn {
+	for _, column := range t.columns {
+		if column.name == name {
+			return column
+		}
+	}
+
+	return nil
+}
+
+func runIntrospect(args []string) error {
+	flags := flag.NewFlagSet("sqbgen introspect", flag.ContinueOnError)
+	input := flags.String("input", "", "pg_dump --schema-only file, or a .json export of information_schema.columns")
+	inputFormat := flags.String("format", "", "input format, sql or json. Defaults to json for .json files and sql otherwise")
+	pkg := flags.String("package", "models", "package name of the generated file")
+	schema := flags.String("schema", "public", "schema whose tables are written")
+	output := flags.String("output", "", "output file, defaults to standard output")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *input == "" {
+		return fmt.Errorf("introspect: -input is required")
+	}
+
+	if *inputFormat == "" {
+		*inputFormat = "sql"
+		if filepath.Ext(*input) == ".json" {
+			*inputFormat = "json"
+		}
+	}
+
+	data, err := os.ReadFile(*input)
+	if err != nil {
+		return err
+	}
+
+	var tables []*schemaTable
+
+	switch *inputFormat {
+	case "sql":
+		tables, err = parseDump(string(data))
+	case "json":
+		tables, err = parseInformationSchema(data)
+	default:
+		return fmt.Errorf("introspect: unknown format %s, expected sql or json", *inputFormat)
+	}
+
+	if err != nil {
+		return fmt.Errorf("introspect: %w", err)
+	}
+
+	src, err := writeModels(*pkg, *schema, tables)
+	if err != nil {
+		return fmt.Errorf("introspect: %w", err)
+	}
+
+	if *output == "" {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+
+	return os.WriteFile(*output, src, 0o644)
+}
+
+var goTypes = map[string]string{
+	"smallint":    "int16",
+	"int2":        "int16",
+	"smallserial": "int16",
+	"serial2":     "int16",
+	"integer":     "int32",
+	"int":         "int32",
+	"int4":        "int32",
+	"serial":      "int32",
+	"serial4":     "int32",
+	"bigint":      "int64",
+	"int8":        "int64",
+	"bigserial":   "int64",
+	"serial8":     "int64",
+
+	"real":             "float32",
+	"float4":           "float32",
+	"double precision": "float64",
+	"float8":           "float64",
+
+	"boolean": "bool",
+	"bool":    "bool",
+
+	"timestamp":                   "time.Time",
+	"timestamp without time zone": "time.Time",
+	"timestamp with time zone":    "time.Time",
+	"timestamptz":                 "time.Time",
+	"date":                        "time.Time",
+
+	"bytea": "[]byte",
+	"json":  "json.RawMessage",
+	"jsonb": "json.RawMessage",
+}
+
+// Serial types are integers with a default taken from a sequence
+var serialTypes = map[string]bool{
+	"smallserial": true,
+	"serial2":     true,
+	"serial":      true,
+	"serial4":     true,
+	"bigserial":   true,
+	"serial8":     true,
+}
+
+// The Go type of a column's field. Types without a mapping, such as numeric, uuid and enums, are read as
+// strings, which keeps numeric's precision.
+func (c *schemaColumn) goType() string {
+	typ, ok := goTypes[c.dataType]
+	if !ok {
+		typ = "string"
+	}
+
+	if c.array {
+		// Arrays are scanned with pq.Array, which supports basic element types and NULL arrays
+		if typ == "time.Time" || typ == "json.RawMessage" {
+			typ = "string"
+		}
+
+		return "[]" + typ
+	}
+
+	if c.nullable && typ != "[]byte" {
+		return "sqb.Null[" + typ + "]"
+	}
+
+	return typ
+}
+
+func (c *schemaColumn) tag() string {
+	parts := []string{c.name}
+
+	if c.primaryKey {
+		parts = append(parts, "pk")
+	}
+
+	if c.generated {
+		parts = append(parts, "readonly")
+	}
+
+	if c.hasDefault && !c.generated {
+		// Tag options are comma separated, so expressions which can't be written in a tag are left out
+		if c.defaultValue != "" && !strings.ContainsAny(c.defaultValue, ",\"`") {
+			parts = append(parts, "default="+c.defaultValue)
+		} else {
+			parts = append(parts, "default")
+		}
+	}
+
+	if len(c.enum) > 0 && !strings.ContainsAny(strings.Join(c.enum, ""), ",|\"`") {
+		parts = append(parts, "enum="+strings.Join(c.enum, "|"))
+	}
+
+	return fmt.Sprintf("`psql:%q`", strings.Join(parts, ","))
+}
+
+// Write a model struct for each table of schema, in name order
+func writeModels(pkg string, schema string, tables []*schemaTable) ([]byte, error) {
+	sort.Slice(tables, func(i, j int) bool { return tables[i].name < tables[j].name })
+
+	var body bytes.Buffer
+	imports := map[string]bool{}
+
+	for _, table := range tables {
+		if table.schema != schema {
+			continue
+		}
+
+		typeName := goName(table.name)
+		fmt.Fprintf(&body, "\n// %s is the model of table %s\ntype %s struct {\n", typeName, table.name, typeName)
+
+		for _, column := range table.columns {
+			typ := column.goType()
+
+			if strings.Contains(typ, "time.") {
+				imports["time"] = true
+			}
+
+			if strings.Contains(typ, "json.") {
+				imports["encoding/json"] = true
+			}
+
+			if strings.Contains(typ, "sqb.") {
+				imports["github.com/themanciraptor/SQb"] = true
+			}
+
+			fmt.Fprintf(&body, "\t%s %s %s\n", goName(column.name), typ, column.tag())
+		}
+
+		body.WriteString("}\n")
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by sqbgen introspect; DO NOT EDIT.\n\npackage %s\n", pkg)
+
+	if len(imports) > 0 {
+		buf.WriteString("\nimport (\n")
+
+		for _, importPath := range []string{"encoding/json", "time"} {
+			if imports[importPath] {
+				fmt.Fprintf(&buf, "\t%q\n", importPath)
+			}
+		}
+
+		if imports[sqbImportPath] {
+			fmt.Fprintf(&buf, "\n\tsqb %q\n", sqbImportPath)
+		}
+
+		buf.WriteString(")\n")
+	}
+
+	buf.Write(body.Bytes())
+
+	return format.Source(buf.Bytes())
+}
+
+// Common initialisms are upper cased, as golint expects
+var initialisms = map[string]bool{
+	"api":  true,
+	"id":   true,
+	"ip":   true,
+	"json": true,
+	"html": true,
+	"http": true,
+	"sql":  true,
+	"uri":  true,
+	"url":  true,
+	"uuid": true,
+}
+
+// Convert a snake_case name to an exported Go name, e.g. user_id to UserID. Quoted camelCase names keep
+// their case, e.g. displayName to DisplayName.
+func goName(name string) string {
+	var b strings.Builder
+
+	words := strings.FieldsFunc(name, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+	for _, word := range words {
+		if initialisms[strings.ToLower(word)] {
+			b.WriteString(strings.ToUpper(word))
+			continue
+		}
+
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+
+	if b.Len() == 0 || unicode.IsDigit([]rune(b.String())[0]) {
+		return "Column" + b.String()
+	}
+
+	return b.String()
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+func Test_Introspect_MatchesGoldenFiles(t *testing.T) {
+	for _, input := range []string{"schema.sql", "columns.json"} {
+		t.Run(input, func(t *testing.T) {
+			output := filepath.Join(t.TempDir(), "models.go")
+			golden := filepath.Join("testdata", input+".golden")
+
+			assert.NoError(t, run([]string{"introspect", "-input", filepath.Join("testdata", input), "-output", output}))
+
+			actual, err := os.ReadFile(output)
+			assert.NoError(t, err)
+
+			if *update {
+				assert.NoError(t, os.WriteFile(golden, actual, 0o644))
+			}
+
+			expected, err := os.ReadFile(golden)
+			assert.NoError(t, err)
+			assert.Equal(t, string(expected), string(actual), "run go test ./cmd/sqbgen -update to update the golden files")
+		})
+	}
+}
+
+func Test_Introspect_WritesOtherSchemas(t *testing.T) {
+	data, err := os.ReadFile("testdata/schema.sql")
+	assert.NoError(t, err)
+
+	tables, err := parseDump(string(data))
+	assert.NoError(t, err)
+
+	src, err := writeModels("audit", "audit", tables)
+	assert.NoError(t, err)
+	assert.Contains(t, string(src), "type Events struct {\n\tID      int64                     `psql:\"id,pk,default\"`\n\tPayload sqb.Null[json.RawMessage] `psql:\"payload\"`\n}")
+	assert.NotContains(t, string(src), "Users")
+}
+
+func Test_Introspect_Errors(t *testing.T) {
+	testCases := map[string]string{
+		"CREATE TABLE t (id integer, note text DEFAULT 'unterminated);": "unterminated quote at offset 46",
+		"CREATE TABLE t (id);": "CREATE TABLE t: column id has no type",
+		"CREATE TABLE t (id integer);\nALTER TABLE t ALTER COLUMN other SET DEFAULT 1;": "ALTER TABLE t: no column other",
+	}
+
+	for src, expected := range testCases {
+		t.Run(expected, func(t *testing.T) {
+			_, err := parseDump(src)
+
+			assert.EqualError(t, err, expected)
+		})
+	}
+}
+
+func Test_GoName(t *testing.T) {
+	testCases := map[string]string{
+		"user_id":     "UserID",
+		"displayName": "DisplayName",
+		"api_url":     "APIURL",
+		"2fa_enabled": "Column2faEnabled",
+		"created_at":  "CreatedAt",
+	}
+
+	for name, expected := range testCases {
+		assert.Equal(t, expected, goName(name), name)
+	}
+}
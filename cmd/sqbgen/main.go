@@ -0,0 +1,84 @@
+/*
+sqbgen generates typed column references and accumulators from SQb table models, so column names and
+filter values are checked by the compiler. It is intended to be run by go generate:
+
+	//go:generate go run github.com/themanciraptor/SQb/cmd/sqbgen -type User,Order
+
+For each listed model, e.g. User, it writes:
+
+	UserColumnCreatedAt	a constant naming each column
+	UserCols.CreatedAt	a sqb.ColumnRef of the field's type, e.g. UserCols.CreatedAt.Gt(t)
+	NewUserAccumulator	an accumulator scanning each row into a User
+
+The introspect command writes models from a database schema, read from a pg_dump --schema-only file or a
+JSON export of information_schema.columns:
+
+	sqbgen introspect -input schema.sql -package models -output models.go
+*/
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("sqbgen: ")
+
+	if err := run(os.Args[1:]); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(args []string) error {
+	if len(args) > 0 && args[0] == "introspect" {
+		return runIntrospect(args[1:])
+	}
+
+	flags := flag.NewFlagSet("sqbgen", flag.ContinueOnError)
+	typeNames := flags.String("type", "", "comma separated list of model types")
+	tag := flags.String("tag", "psql", "struct tag naming each field's column")
+	snake := flags.Bool("snake", false, "name untagged exported fields in snake_case, like sqb.WithNamingStrategy(sqb.SnakeCase)")
+	output := flags.String("output", "", "output file, defaults to <package>_sqb.go in the package directory")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *typeNames == "" {
+		return fmt.Errorf("-type is required")
+	}
+
+	dir := "."
+	if flags.NArg() > 0 {
+		dir = flags.Arg(0)
+	}
+
+	config := generateConfig{
+		dir:   dir,
+		types: strings.Split(*typeNames, ","),
+		tag:   *tag,
+		snake: *snake,
+	}
+
+	if *output != "" {
+		config.output = filepath.Base(*output)
+	}
+
+	pkg, src, err := generate(config)
+	if err != nil {
+		return err
+	}
+
+	path := *output
+	if path == "" {
+		path = filepath.Join(dir, pkg+"_sqb.go")
+	}
+
+	return os.WriteFile(path, src, 0o644)
+}
@@ -0,0 +1,471 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+/*
+	A small reader for the statements pg_dump --schema-only writes, enough to recover each table's columns.
+	It understands CREATE TABLE, CREATE TYPE ... AS ENUM, and the ALTER TABLE statements pg_dump uses to add
+	primary keys, defaults and identity columns. Other statements are skipped.
+*/
+
+type sqlToken struct {
+	text   string // Lower cased for unquoted words
+	quoted bool   // A quoted identifier
+	start  int    // Offsets of the token in the source, so expressions can be copied verbatim
+	end    int
+}
+
+func (t sqlToken) is(words ...string) bool {
+	if t.quoted {
+		return false
+	}
+
+	for _, word := range words {
+		if t.text == word {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Split SQL into tokens, skipping comments. Strings, including dollar quoted strings, are single tokens.
+func tokenizeSQL(src string) ([]sqlToken, error) {
+	var tokens []sqlToken
+
+	for i := 0; i < len(src); {
+		c := src[i]
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case strings.HasPrefix(src[i:], "--"):
+			end := strings.IndexByte(src[i:], '\n')
+			if end < 0 {
+				end = len(src) - i
+			}
+
+			i += end
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated comment")
+			}
+
+			i += end + 2
+		case c == '\'' || c == '"':
+			end := i + 1
+			for ; end < len(src); end++ {
+				if src[end] == c {
+					// Quotes are escaped by doubling them
+					if end+1 < len(src) && src[end+1] == c {
+						end++
+						continue
+					}
+
+					break
+				}
+			}
+
+			if end >= len(src) {
+				return nil, fmt.Errorf("unterminated quote at offset %d", i)
+			}
+
+			token := sqlToken{text: src[i : end+1], start: i, end: end + 1}
+			if c == '"' {
+				token.text = strings.ReplaceAll(src[i+1:end], `""`, `"`)
+				token.quoted = true
+			}
+
+			tokens = append(tokens, token)
+			i = end + 1
+		case c == '$' && dollarTag(src[i:]) != "":
+			tag := dollarTag(src[i:])
+
+			end := strings.Index(src[i+len(tag):], tag)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated dollar quote at offset %d", i)
+			}
+
+			end += i + 2*len(tag)
+			tokens = append(tokens, sqlToken{text: src[i:end], start: i, end: end})
+			i = end
+		case isWordByte(c):
+			end := i
+			for end < len(src) && isWordByte(src[end]) {
+				end++
+			}
+
+			tokens = append(tokens, sqlToken{text: strings.ToLower(src[i:end]), start: i, end: end})
+			i = end
+		default:
+			tokens = append(tokens, sqlToken{text: string(c), start: i, end: i + 1})
+			i++
+		}
+	}
+
+	return tokens, nil
+}
+
+// The opening tag of a dollar quoted string, e.g. $$ or $body$
+func dollarTag(src string) string {
+	for i := 1; i < len(src); i++ {
+		if src[i] == '$' {
+			return src[:i+1]
+		}
+
+		if !isWordByte(src[i]) || (src[i] >= '0' && src[i] <= '9') {
+			return ""
+		}
+	}
+
+	return ""
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// Split tokens on sep outside of parentheses and brackets
+func splitTokens(tokens []sqlToken, sep string) [][]sqlToken {
+	var parts [][]sqlToken
+
+	depth, start := 0, 0
+	for i, token := range tokens {
+		switch {
+		case token.is("(", "["):
+			depth++
+		case token.is(")", "]"):
+			depth--
+		case depth == 0 && token.is(sep):
+			parts = append(parts, tokens[start:i])
+			start = i + 1
+		}
+	}
+
+	if start < len(tokens) {
+		parts = append(parts, tokens[start:])
+	}
+
+	return parts
+}
+
+// The tokens between the parentheses opening at tokens[0], and the tokens after them
+func parenthesised(tokens []sqlToken) ([]sqlToken, []sqlToken, bool) {
+	if len(tokens) == 0 || !tokens[0].is("(") {
+		return nil, nil, false
+	}
+
+	depth := 0
+	for i, token := range tokens {
+		if token.is("(") {
+			depth++
+		} else if token.is(")") {
+			depth--
+			if depth == 0 {
+				return tokens[1:i], tokens[i+1:], true
+			}
+		}
+	}
+
+	return nil, nil, false
+}
+
+// Read a possibly schema qualified name, returning the schema, name and remaining tokens
+func qualifiedName(tokens []sqlToken) (string, string, []sqlToken) {
+	if len(tokens) == 0 {
+		return "", "", nil
+	}
+
+	if len(tokens) >= 3 && tokens[1].is(".") {
+		return tokens[0].text, tokens[2].text, tokens[3:]
+	}
+
+	return "public", tokens[0].text, tokens[1:]
+}
+
+// Skip the leading words if they are present
+func skip(tokens []sqlToken, words ...string) ([]sqlToken, bool) {
+	if len(tokens) < len(words) {
+		return tokens, false
+	}
+
+	for i, word := range words {
+		if !tokens[i].is(word) {
+			return tokens, false
+		}
+	}
+
+	return tokens[len(words):], true
+}
+
+type dumpReader struct {
+	src    string
+	tables map[string]*schemaTable
+	order  []*schemaTable
+	enums  map[string][]string
+}
+
+func parseDump(src string) ([]*schemaTable, error) {
+	tokens, err := tokenizeSQL(src)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &dumpReader{src: src, tables: map[string]*schemaTable{}, enums: map[string][]string{}}
+
+	for _, statement := range splitTokens(tokens, ";") {
+		if err := r.statement(statement); err != nil {
+			return nil, err
+		}
+	}
+
+	return r.order, nil
+}
+
+func (r *dumpReader) statement(tokens []sqlToken) error {
+	if rest, ok := skip(tokens, "create", "type"); ok {
+		return r.createType(rest)
+	}
+
+	if rest, ok := skip(tokens, "create"); ok {
+		rest, _ = skip(rest, "unlogged")
+		if rest, ok := skip(rest, "table"); ok {
+			return r.createTable(rest)
+		}
+	}
+
+	if rest, ok := skip(tokens, "alter", "table"); ok {
+		rest, _ = skip(rest, "if", "exists")
+		rest, _ = skip(rest, "only")
+		return r.alterTable(rest)
+	}
+
+	return nil
+}
+
+func (r *dumpReader) createType(tokens []sqlToken) error {
+	schema, name, rest := qualifiedName(tokens)
+
+	rest, ok := skip(rest, "as", "enum")
+	if !ok {
+		return nil
+	}
+
+	values, _, ok := parenthesised(rest)
+	if !ok {
+		return fmt.Errorf("CREATE TYPE %s: expected a list of values", name)
+	}
+
+	var enum []string
+	for _, value := range splitTokens(values, ",") {
+		if len(value) != 1 || !strings.HasPrefix(value[0].text, "'") {
+			return fmt.Errorf("CREATE TYPE %s: expected a string value", name)
+		}
+
+		enum = append(enum, strings.ReplaceAll(strings.Trim(value[0].text, "'"), "''", "'"))
+	}
+
+	r.enums[schema+"."+name] = enum
+
+	return nil
+}
+
+func (r *dumpReader) createTable(tokens []sqlToken) error {
+	tokens, _ = skip(tokens, "if", "not", "exists")
+	schema, name, rest := qualifiedName(tokens)
+
+	definitions, _, ok := parenthesised(rest)
+	if !ok {
+		// e.g. CREATE TABLE ... PARTITION OF, whose columns come from the parent
+		return nil
+	}
+
+	table := &schemaTable{schema: schema, name: name}
+
+	for _, definition := range splitTokens(definitions, ",") {
+		if len(definition) == 0 {
+			continue
+		}
+
+		if definition[0].is("constraint", "primary", "unique", "check", "foreign", "exclude", "like") {
+			table.constraint(definition)
+			continue
+		}
+
+		column, err := r.column(definition)
+		if err != nil {
+			return fmt.Errorf("CREATE TABLE %s: %w", name, err)
+		}
+
+		table.columns = append(table.columns, column)
+	}
+
+	r.tables[schema+"."+name] = table
+	r.order = append(r.order, table)
+
+	return nil
+}
+
+// Words ending a column's type
+var columnConstraints = []string{"not", "null", "default", "primary", "constraint", "references", "unique", "check", "generated", "collate"}
+
+func (r *dumpReader) column(tokens []sqlToken) (*schemaColumn, error) {
+	column := &schemaColumn{name: tokens[0].text, nullable: true}
+
+	typeEnd := 1
+	for typeEnd < len(tokens) && !tokens[typeEnd].is(columnConstraints...) {
+		typeEnd++
+	}
+
+	if typeEnd == 1 {
+		return nil, fmt.Errorf("column %s has no type", column.name)
+	}
+
+	r.columnType(column, tokens[1:typeEnd])
+
+	rest := tokens[typeEnd:]
+	for len(rest) > 0 {
+		var ok bool
+
+		switch {
+		case rest[0].is("not"):
+			rest, _ = skip(rest, "not", "null")
+			column.nullable = false
+		case rest[0].is("primary"):
+			rest, _ = skip(rest, "primary", "key")
+			column.primaryKey = true
+			column.nullable = false
+		case rest[0].is("default"):
+			end := 1
+			for end < len(rest) && !rest[end].is(columnConstraints...) {
+				end++
+			}
+
+			column.hasDefault = true
+			column.defaultValue = r.src[rest[1].start:rest[end-1].end]
+			rest = rest[end:]
+		case rest[0].is("generated"):
+			if rest, ok = skip(rest, "generated", "always"); ok {
+				column.generated = true
+			} else {
+				column.hasDefault = true
+				rest = rest[1:]
+			}
+		default:
+			rest = rest[1:]
+		}
+	}
+
+	return column, nil
+}
+
+// Read a type such as character varying(255), timestamp(3) with time zone, public.status or integer[]
+func (r *dumpReader) columnType(column *schemaColumn, tokens []sqlToken) {
+	var words []string
+
+	for len(tokens) > 0 {
+		token := tokens[0]
+
+		switch {
+		case token.is("("):
+			_, rest, ok := parenthesised(tokens)
+			if !ok {
+				return
+			}
+
+			tokens = rest
+			continue
+		case token.is("[", "array"):
+			column.array = true
+		case token.is("]", "."):
+		default:
+			words = append(words, token.text)
+		}
+
+		tokens = tokens[1:]
+	}
+
+	column.dataType = strings.Join(words, " ")
+
+	if serialTypes[column.dataType] {
+		column.hasDefault = true
+		column.nullable = false
+	}
+
+	// User defined types are named by their last word, possibly after a schema
+	typeName := words[len(words)-1]
+	schema := "public"
+	if len(words) == 2 {
+		schema = words[0]
+	}
+
+	if enum, ok := r.enums[schema+"."+typeName]; ok {
+		column.dataType = typeName
+		column.enum = enum
+	}
+}
+
+// Record the primary key of a table constraint
+func (t *schemaTable) constraint(tokens []sqlToken) {
+	if tokens[0].is("constraint") && len(tokens) > 2 {
+		tokens = tokens[2:]
+	}
+
+	rest, ok := skip(tokens, "primary", "key")
+	if !ok {
+		return
+	}
+
+	columns, _, ok := parenthesised(rest)
+	if !ok {
+		return
+	}
+
+	for _, name := range splitTokens(columns, ",") {
+		if column := t.column(name[0].text); column != nil {
+			column.primaryKey = true
+			column.nullable = false
+		}
+	}
+}
+
+func (r *dumpReader) alterTable(tokens []sqlToken) error {
+	schema, name, rest := qualifiedName(tokens)
+
+	table, ok := r.tables[schema+"."+name]
+	if !ok {
+		return nil
+	}
+
+	for _, action := range splitTokens(rest, ",") {
+		if constraint, ok := skip(action, "add"); ok {
+			table.constraint(constraint)
+			continue
+		}
+
+		alter, ok := skip(action, "alter", "column")
+		if !ok || len(alter) < 2 {
+			continue
+		}
+
+		column := table.column(alter[0].text)
+		if column == nil {
+			return fmt.Errorf("ALTER TABLE %s: no column %s", name, alter[0].text)
+		}
+
+		if expression, ok := skip(alter[1:], "set", "default"); ok && len(expression) > 0 {
+			column.hasDefault = true
+			column.defaultValue = r.src[expression[0].start:expression[len(expression)-1].end]
+		} else if _, ok := skip(alter[1:], "add", "generated", "always"); ok {
+			column.generated = true
+		} else if _, ok := skip(alter[1:], "add", "generated"); ok {
+			column.hasDefault = true
+		}
+	}
+
+	return nil
+}
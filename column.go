@@ -8,18 +8,95 @@ import "reflect"
 */
 
 type Column struct {
-	kind     reflect.Kind
+	name     string
+	typ      reflect.Type
 	receiver interface{}
+
+	// The values an enum column may hold, empty for other columns
+	enum []string
+
+	// Index of the column's field within the table model, see reflect.Value.FieldByIndex
+	index []int
+
+	// Options set by the model's struct tags
+	primaryKey   bool
+	readonly     bool
+	omitEmpty    bool
+	hasDefault   bool
+	defaultValue string
+
+	// Every select, update and delete must filter by the column, see Table.Scope
+	scope bool
+
+	// DDL options, see CreateTableSQL
+	sqlType    string
+	sqlIndexes []sqlIndex
+}
+
+// An index on a column, named by the index or unique tag option. Unnamed indexes cover a single column.
+type sqlIndex struct {
+	name   string
+	unique bool
+}
+
+func (s *Column) PrimaryKey() bool {
+	return s.primaryKey
+}
+
+// Whether a value should be written to the column, given the value of its field
+func (s *Column) writable(v reflect.Value) bool {
+	if s.readonly {
+		return false
+	}
+
+	return !((s.omitEmpty || s.hasDefault) && v.IsZero())
+}
+
+func (s *Column) Scope() bool {
+	return s.scope
+}
+
+func (s *Column) Name() string {
+	return s.name
+}
+
+// The Go type of the column's field in the table model
+func (s *Column) Type() reflect.Type {
+	return s.typ
 }
 
 func (s *Column) SetReceiver(v interface{}) {
 	s.receiver = v
 }
 
-// must be initialized with a columnKind or the column would be unable to perform typeChecking
-func NewColumn(columnKind reflect.Kind) *Column {
+// must be initialized with a columnType or the column would be unable to perform typeChecking
+func NewColumn(columnType reflect.Type) *Column {
 	return &Column{
-		kind:     columnKind,
+		typ:      columnType,
 		receiver: nil,
 	}
 }
+
+// Copy the column's definition without its receiver
+func (s *Column) clone() *Column {
+	c := *s
+	c.receiver = nil
+
+	return &c
+}
+
+// Adapt receiver so that the driver can scan into it, validating values for enum columns
+func (s *Column) scanTarget(receiver interface{}) interface{} {
+	target := scanTarget(receiver)
+
+	if len(s.enum) > 0 {
+		return &enumScanner{column: s, dest: target}
+	}
+
+	return target
+}
+
+// Whether values of type t may be compared with, scanned from or written to this column
+func (s *Column) accepts(t reflect.Type) bool {
+	return TypesCompatible(s.typ, t)
+}
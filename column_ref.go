@@ -0,0 +1,86 @@
+package sqb
+
+/*
+	A ColumnRef names a column whose values have the Go type V, so the values a filter compares it with are
+	checked by the compiler rather than when the query is built. ColumnRefs are usually generated from table
+	models by cmd/sqbgen, and filters made from them are added to a table with Where.
+*/
+
+type ColumnRef[V any] struct {
+	name string
+}
+
+func NewColumnRef[V any](name string) ColumnRef[V] {
+	return ColumnRef[V]{name: name}
+}
+
+func (c ColumnRef[V]) Name() string {
+	return c.name
+}
+
+// A filter on a single column, made by a ColumnRef
+type ColumnFilter struct {
+	column   string
+	operator string
+	values   []interface{}
+}
+
+func (c ColumnRef[V]) Eq(v V) ColumnFilter {
+	return c.compare("=", v)
+}
+
+func (c ColumnRef[V]) NotEq(v V) ColumnFilter {
+	return c.compare("<>", v)
+}
+
+func (c ColumnRef[V]) Gt(v V) ColumnFilter {
+	return c.compare(">", v)
+}
+
+func (c ColumnRef[V]) Gte(v V) ColumnFilter {
+	return c.compare(">=", v)
+}
+
+func (c ColumnRef[V]) Lt(v V) ColumnFilter {
+	return c.compare("<", v)
+}
+
+func (c ColumnRef[V]) Lte(v V) ColumnFilter {
+	return c.compare("<=", v)
+}
+
+func (c ColumnRef[V]) In(values ...V) ColumnFilter {
+	f := ColumnFilter{column: c.name, operator: "IN"}
+	for _, v := range values {
+		f.values = append(f.values, v)
+	}
+
+	return f
+}
+
+func (c ColumnRef[V]) IsNull() ColumnFilter {
+	return ColumnFilter{column: c.name, operator: "IS NULL"}
+}
+
+func (c ColumnRef[V]) compare(operator string, v V) ColumnFilter {
+	return ColumnFilter{column: c.name, operator: operator, values: []interface{}{v}}
+}
+
+// Add filters made by ColumnRefs. The filters are checked in the same way as ColumnEquals and friends.
+func (t *Table[T]) Where(filters ...ColumnFilter) *Table[T] {
+	for _, f := range filters {
+		switch f.operator {
+		case "IN":
+			t.ColumnIn(f.column, f.values...)
+		case "IS NULL":
+			t.ColumnNull(f.column)
+		default:
+			t.AssertFilterClauseValid(f.column, f.values[0])
+			t.checkEnumValue(f.column, f.values[0])
+
+			t.filter.AddClause(NewPrimitiveFilterClause(t.columnRef(f.column), f.operator, "%s", f.values[0]))
+		}
+	}
+
+	return t
+}
@@ -0,0 +1,27 @@
+package sqb_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	sqb "github.com/themanciraptor/SQb"
+)
+
+func Test_Where_BuildsColumnRefFilters(t *testing.T) {
+	cool := sqb.NewColumnRef[string]("cool")
+	stars := sqb.NewColumnRef[int]("number_of_star")
+
+	params := sqb.NewParamList(sqb.Psql())
+	tt := sqb.NewTable[exampleResult]("exampleTable", sqb.Psql(), &exampleModel{}).
+		Where(cool.NotEq("doom"), stars.Gte(3), stars.Lt(10), cool.In("a", "b")).
+		Where(cool.IsNull())
+
+	assert.Equal(t, "(cool <> $1 AND number_of_star >= $2 AND number_of_star < $3 AND cool IN ($4, $5) AND cool IS NULL)", tt.BuildFilter(params))
+	assert.Equal(t, []interface{}{"doom", 3, 10, "a", "b"}, params.GetParamList())
+}
+
+func Test_Where_ChecksColumns(t *testing.T) {
+	assert.PanicsWithValue(t, "Incorrect type for column. Need string, got int", func() {
+		sqb.NewTable[exampleResult]("exampleTable", sqb.Psql(), &exampleModel{}).Where(sqb.NewColumnRef[int]("cool").Eq(1))
+	})
+}
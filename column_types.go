@@ -0,0 +1,128 @@
+package sqb
+
+import (
+	"reflect"
+	"sync"
+)
+
+/*
+	Column types are the Go types of the fields in a table model. Filter values, receivers and written values
+	are checked against them using the rules below, so that e.g. a time.Time column cannot be filtered with
+	some other struct, while an int32 column can still be scanned into an int64.
+
+	The built in rules treat types as compatible when they are:
+	  - identical
+	  - both signed integers, both unsigned integers or both floats, allowing widening and narrowing
+	  - both strings or both bools, allowing string backed enums
+	  - both byte slices, e.g. []byte and json.RawMessage
+	  - both json, i.e. JSON wrappers of any type, or json.RawMessage
+	  - slices of compatible elements
+	Nullable wrappers such as Null[T], and pointers, are compatible with whatever their wrapped type is
+	compatible with.
+
+	Anything else can be allowed by registering a TypeRule.
+*/
+
+// A TypeRule reports whether values of type value may be used with a column of type column. Rules are
+// consulted after the built in rules, any rule accepting a pair makes the types compatible.
+type TypeRule func(column reflect.Type, value reflect.Type) bool
+
+var (
+	typeRulesMu sync.RWMutex
+	typeRules   []TypeRule
+)
+
+// Register an additional rule used by every table to check column types. Intended to be called during
+// program initialization.
+func RegisterTypeRule(rule TypeRule) {
+	typeRulesMu.Lock()
+	defer typeRulesMu.Unlock()
+
+	typeRules = append(typeRules, rule)
+}
+
+// Whether values of type value may be used with a column of type column
+func TypesCompatible(column reflect.Type, value reflect.Type) bool {
+	if column == nil || value == nil {
+		return false
+	}
+
+	column = unwrapNullable(column)
+	value = unwrapNullable(value)
+
+	if column == value || builtinTypesCompatible(column, value) {
+		return true
+	}
+
+	typeRulesMu.RLock()
+	defer typeRulesMu.RUnlock()
+
+	for _, rule := range typeRules {
+		if rule(column, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Nullable wrappers and pointers are checked using the type they hold
+func unwrapNullable(t reflect.Type) reflect.Type {
+	for {
+		if v, ok := nullValueType(t); ok {
+			t = v
+		} else if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		} else {
+			return t
+		}
+	}
+}
+
+func builtinTypesCompatible(column reflect.Type, value reflect.Type) bool {
+	switch {
+	case isSignedInt(column.Kind()) && isSignedInt(value.Kind()):
+		return true
+	case isUnsignedInt(column.Kind()) && isUnsignedInt(value.Kind()):
+		return true
+	case isFloat(column.Kind()) && isFloat(value.Kind()):
+		return true
+	case column.Kind() == reflect.String && value.Kind() == reflect.String:
+		return true
+	case column.Kind() == reflect.Bool && value.Kind() == reflect.Bool:
+		return true
+	case isJSONType(column) && isJSONType(value):
+		return true
+	case column.Kind() == reflect.Slice && value.Kind() == reflect.Slice:
+		if column.Elem().Kind() == reflect.Uint8 && value.Elem().Kind() == reflect.Uint8 {
+			return true
+		}
+
+		return TypesCompatible(column.Elem(), value.Elem())
+	}
+
+	return false
+}
+
+func isSignedInt(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	}
+
+	return false
+}
+
+// Excludes uintptr, which is never a column value
+func isUnsignedInt(k reflect.Kind) bool {
+	switch k {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+
+	return false
+}
+
+func isFloat(k reflect.Kind) bool {
+	return k == reflect.Float32 || k == reflect.Float64
+}
@@ -0,0 +1,102 @@
+package sqb_test
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	sqb "github.com/themanciraptor/SQb"
+)
+
+type exampleStatus string
+
+type exampleUUID [16]byte
+
+func (u *exampleUUID) Scan(src any) error {
+	copy(u[:], src.([]byte))
+	return nil
+}
+
+func (u exampleUUID) Value() (driver.Value, error) {
+	return u[:], nil
+}
+
+type exampleTypesModel struct {
+	Count   int             `psql:"count"`
+	Size    uint64          `psql:"size"`
+	Blob    []byte          `psql:"blob"`
+	Attrs   json.RawMessage `psql:"attrs"`
+	ID      exampleUUID     `psql:"id"`
+	Status  exampleStatus   `psql:"status"`
+	Created time.Time       `psql:"created"`
+}
+
+func Test_TypesCompatible(t *testing.T) {
+	type testCase struct {
+		description string
+		column      any
+		value       any
+		expected    bool
+	}
+
+	testCases := []testCase{
+		{"identical types", int64(0), int64(0), true},
+		{"int32 widens to int64", int64(0), int32(0), true},
+		{"int64 narrows to int32", int32(0), int64(0), true},
+		{"int and int64", 0, int64(0), true},
+		{"unsigned and signed", uint64(0), int64(0), false},
+		{"float32 and float64", float64(0), float32(0), true},
+		{"string backed enum", exampleStatus(""), "", true},
+		{"byte slice and raw json", json.RawMessage{}, []byte{}, true},
+		{"string slices", []string{}, []string{}, true},
+		{"string slice and int slice", []string{}, []int64{}, false},
+		{"time and nullable time", time.Time{}, sqb.NullTime{}, true},
+		{"nullable int32 and int64", int64(0), sqb.NullInt32{}, true},
+		{"time and other struct", time.Time{}, struct{}{}, false},
+		{"other struct and nullable time", struct{}{}, sqb.NullTime{}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			assert.Equal(t, tc.expected, sqb.TypesCompatible(reflect.TypeOf(tc.column), reflect.TypeOf(tc.value)))
+		})
+	}
+}
+
+func Test_RegisterTypeRule_AllowsAdditionalTypes(t *testing.T) {
+	uuidType := reflect.TypeOf(exampleUUID{})
+
+	assert.False(t, sqb.TypesCompatible(uuidType, reflect.TypeOf("")))
+
+	sqb.RegisterTypeRule(func(column reflect.Type, value reflect.Type) bool {
+		return column == uuidType && value.Kind() == reflect.String
+	})
+
+	assert.True(t, sqb.TypesCompatible(uuidType, reflect.TypeOf("")))
+	assert.False(t, sqb.TypesCompatible(reflect.TypeOf(""), uuidType))
+}
+
+func Test_Table_ChecksRealColumnTypes(t *testing.T) {
+	tt := sqb.NewTable[any]("types", sqb.Psql(), &exampleTypesModel{})
+
+	assert.NotPanics(t, func() {
+		tt.ColumnEquals("count", int64(1)).
+			ColumnEquals("size", uint64(1)).
+			ColumnEquals("status", "open").
+			SetColumnReceiver("blob", new([]byte)).
+			SetColumnReceiver("attrs", new(json.RawMessage)).
+			SetColumnReceiver("id", new(exampleUUID)).
+			SetColumnReceiver("created", sqb.NewNullTime(new(time.Time)))
+	})
+
+	assert.PanicsWithValue(t, "Incorrect type for column. Need time.Time, got struct {}", func() {
+		tt.ColumnEquals("created", struct{}{})
+	})
+
+	assert.PanicsWithValue(t, "SetField: attempted to scan to invalid type for field: cannot assign int64 to: uint64", func() {
+		tt.SetColumnReceiver("size", new(int64))
+	})
+}
@@ -47,6 +47,10 @@ func (r *exampleResultAccumulator) Acc() {
 	r.results = append(r.results, *r.receiver)
 }
 
+func (r *exampleResultAccumulator) Reset() {
+	*r.receiver = exampleResult{}
+}
+
 func (r *exampleResultAccumulator) GetColumnReceiverMap() map[string]interface{} {
 	return r.receiverMap
 }
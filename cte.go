@@ -0,0 +1,120 @@
+package sqb
+
+import (
+	"fmt"
+	"strings"
+)
+
+/*
+	A CTE is a named select statement, defined with WITH name AS (...) before the query that uses it. Its
+	columns are those selected by its body, so a table created from it with FromCTE is type checked in the
+	same way as a table created from a model.
+*/
+
+type CTE struct {
+	name    string
+	columns []*Column
+
+	base Selectable
+	// The recursive term, unioned with base. Only set for recursive CTEs
+	step Selectable
+}
+
+func NewCTE(name string, body Selectable) *CTE {
+	return &CTE{
+		name:    name,
+		columns: copyColumns(body.ResultColumns()),
+		base:    body,
+	}
+}
+
+// Recursive CTEs walk hierarchies such as trees. The base rows are unioned with the rows produced by step,
+// which is given a table for the CTE itself to join against. step must select the same columns as base.
+//
+//	tree := sqb.NewRecursiveCTE("tree", roots, func(self *sqb.Table[any]) sqb.Selectable {
+//		return employees.Select("id", "manager_id").Join(self, "manager_id", "id")
+//	})
+func NewRecursiveCTE(name string, base Selectable, step func(self *Table[any]) Selectable) *CTE {
+	c := NewCTE(name, base)
+	c.step = step(FromCTE[any](c))
+
+	stepColumns := c.step.ResultColumns()
+	if len(stepColumns) != len(c.columns) {
+		panic(fmt.Sprintf("Recursive CTE %s: step must select %d columns, got %d", name, len(c.columns), len(stepColumns)))
+	}
+
+	for i, column := range c.columns {
+		if !column.accepts(stepColumns[i].typ) {
+			panic(fmt.Sprintf("Recursive CTE %s: incorrect type for column %s. Need %s, got %s", name, column.name, column.typ, stepColumns[i].typ))
+		}
+	}
+
+	return c
+}
+
+func (c *CTE) Name() string {
+	return c.name
+}
+
+func (c *CTE) Recursive() bool {
+	return c.step != nil
+}
+
+func (c *CTE) Build(params *ParamList) string {
+	body := c.base.BuildSelect(params)
+	if c.step != nil {
+		body = fmt.Sprint(body, " UNION ALL ", c.step.BuildSelect(params))
+	}
+
+	return fmt.Sprintf("%s AS (%s)", c.name, body)
+}
+
+// Create a table which selects from a CTE. The CTE must be attached to the query with With.
+func FromCTE[T any](c *CTE) *Table[T] {
+	table := &Table[T]{
+		tableName: c.name,
+		fields:    map[string]*Column{},
+		filter:    NewCompoundClause("AND"),
+		scopes:    map[string]interface{}{},
+	}
+
+	for _, column := range copyColumns(c.columns) {
+		table.fields[column.name] = column
+	}
+
+	return table
+}
+
+func buildWith(ctes []*CTE, params *ParamList) string {
+	if len(ctes) == 0 {
+		return ""
+	}
+
+	keyword := "WITH "
+	built := make([]string, 0, len(ctes))
+
+	for _, c := range ctes {
+		if c.Recursive() {
+			keyword = "WITH RECURSIVE "
+		}
+
+		built = append(built, c.Build(params))
+	}
+
+	return fmt.Sprint(keyword, strings.Join(built, ", "), " ")
+}
+
+// Columns are copied without their receivers, so that receivers set on the source are not scanned into
+func copyColumns(columns []*Column) []*Column {
+	copied := make([]*Column, 0, len(columns))
+
+	for _, column := range columns {
+		c := NewColumn(column.typ)
+		c.name = column.name
+		c.enum = column.enum
+		c.primaryKey = column.primaryKey
+		copied = append(copied, c)
+	}
+
+	return copied
+}
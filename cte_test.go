@@ -0,0 +1,149 @@
+package sqb_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	sqb "github.com/themanciraptor/SQb"
+)
+
+type exampleEmployeeModel struct {
+	ID        int64  `psql:"id"`
+	ManagerID int64  `psql:"manager_id"`
+	Name      string `psql:"name"`
+}
+
+type exampleEmployee struct {
+	ID   int64
+	Name string
+}
+
+func Test_CTE_CanBeSelectedFrom(t *testing.T) {
+	open := sqb.NewCTE("open_orders", sqb.NewTable[any]("orders", sqb.Psql(), &exampleOrderModel{}).
+		Select("owner", "stars").
+		ColumnEquals("status", "open"))
+
+	r := exampleResult{}
+	q := sqb.FromCTE[exampleResult](open).
+		With(open).
+		SetColumnReceiver("owner", &r.Name).
+		ColumnEquals("stars", int64(5)).
+		Build(&exampleResultAccumulator{}, sqb.Psql())
+
+	assert.Equal(t, "WITH open_orders AS (SELECT owner, stars FROM orders WHERE status = $1) SELECT owner FROM open_orders WHERE stars = $2", q.GetQuery())
+	assert.Equal(t, []interface{}{"open", int64(5)}, q.GetParams())
+}
+
+func Test_CTE_CanBeUsedInSubqueriesAndJoins(t *testing.T) {
+	open := sqb.NewCTE("open_orders", sqb.NewTable[any]("orders", sqb.Psql(), &exampleOrderModel{}).
+		Select("owner").
+		ColumnEquals("status", "open"))
+
+	r := exampleResult{}
+	subquery := sqb.NewTable[exampleResult]("exampleTable", sqb.Psql(), &exampleModel{}).
+		With(open).
+		SetColumnReceiver("cool", &r.Name).
+		ColumnInSubquery("cool", sqb.FromCTE[any](open).Select("owner")).
+		Build(&exampleResultAccumulator{}, sqb.Psql())
+
+	assert.Equal(t, "WITH open_orders AS (SELECT owner FROM orders WHERE status = $1) SELECT cool FROM exampleTable WHERE cool IN (SELECT owner FROM open_orders)", subquery.GetQuery())
+
+	join := sqb.NewTable[exampleResult]("exampleTable", sqb.Psql(), &exampleModel{}).
+		With(open).
+		SetColumnReceiver("cool", &r.Name).
+		Join(sqb.FromCTE[any](open), "cool", "owner").
+		Build(&exampleResultAccumulator{}, sqb.Psql())
+
+	assert.Equal(t, "WITH open_orders AS (SELECT owner FROM orders WHERE status = $1) SELECT exampleTable.cool FROM exampleTable JOIN open_orders ON exampleTable.cool = open_orders.owner", join.GetQuery())
+}
+
+// Both sides have id columns, so every column of the table is qualified, including those filtered before
+// the join was added
+func Test_Join_QualifiesColumns(t *testing.T) {
+	managers := sqb.NewTable[any]("managers", sqb.Psql(), &exampleEmployeeModel{})
+
+	params := sqb.NewParamList(sqb.Psql())
+	query := sqb.NewTable[any]("employees", sqb.Psql(), &exampleEmployeeModel{}).
+		Select("id", "name").
+		ColumnEquals("id", int64(3)).
+		Join(managers, "manager_id", "id").
+		ColumnIn("name", "a", "b").
+		AddOrderByClause("id", sqb.Descending).
+		BuildSelect(params)
+
+	assert.Equal(t, "SELECT employees.id, employees.name FROM employees JOIN managers ON employees.manager_id = managers.id "+
+		"WHERE (employees.id = $1 AND employees.name IN ($2, $3)) ORDER BY employees.id DESC", query)
+	assert.Equal(t, []interface{}{int64(3), "a", "b"}, params.GetParamList())
+}
+
+func Test_Join_FiltersBySourceFilters(t *testing.T) {
+	managers := sqb.NewTable[any]("managers", sqb.Psql(), &exampleEmployeeModel{}).ColumnEquals("name", "doom")
+
+	params := sqb.NewParamList(sqb.Psql())
+	query := sqb.NewTable[any]("employees", sqb.Psql(), &exampleEmployeeModel{}).
+		Select("name").
+		Join(managers, "manager_id", "id").
+		ColumnEquals("id", int64(3)).
+		BuildSelect(params)
+
+	assert.NoError(t, params.Err())
+	assert.Equal(t, "SELECT employees.name FROM employees JOIN managers ON employees.manager_id = managers.id AND managers.name = $1 "+
+		"WHERE employees.id = $2", query)
+	assert.Equal(t, []interface{}{"doom", int64(3)}, params.GetParamList())
+
+	params = sqb.NewParamList(sqb.Psql())
+	sqb.NewTable[any]("employees", sqb.Psql(), &exampleEmployeeModel{}).
+		Select("name").
+		Join(sqb.NewTable[any]("managers", sqb.Psql(), &exampleEmployeeModel{}).AddOrderByClause("id", sqb.Ascending), "manager_id", "id").
+		BuildSelect(params)
+
+	assert.EqualError(t, params.Err(), "Join: source managers has joins, CTEs, ORDER BY or LIMIT, which cannot be joined")
+}
+
+func Test_RecursiveCTE_WalksHierarchy(t *testing.T) {
+	employees := func() *sqb.Table[any] {
+		return sqb.NewTable[any]("employees", sqb.Psql(), &exampleEmployeeModel{})
+	}
+
+	tree := sqb.NewRecursiveCTE("tree",
+		employees().Select("id", "manager_id", "name").ColumnEquals("id", int64(1)),
+		func(self *sqb.Table[any]) sqb.Selectable {
+			return employees().Select("id", "manager_id", "name").Join(self, "manager_id", "id")
+		},
+	)
+
+	r := exampleEmployee{}
+	q := sqb.FromCTE[exampleEmployee](tree).
+		With(tree).
+		SetColumnReceiver("id", &r.ID).
+		SetColumnReceiver("name", &r.Name).
+		Build(sqb.NewAccumulator(func(r *exampleEmployee) map[string]interface{} { return nil }), sqb.Psql())
+
+	assert.Equal(t, "WITH RECURSIVE tree AS ("+
+		"SELECT id, manager_id, name FROM employees WHERE id = $1 "+
+		"UNION ALL "+
+		"SELECT employees.id, employees.manager_id, employees.name FROM employees JOIN tree ON employees.manager_id = tree.id"+
+		") SELECT id, name FROM tree", q.GetQuery())
+	assert.Equal(t, []interface{}{int64(1)}, q.GetParams())
+}
+
+func Test_CTE_ColumnsAreTypeChecked(t *testing.T) {
+	open := sqb.NewCTE("open_orders", sqb.NewTable[any]("orders", sqb.Psql(), &exampleOrderModel{}).Select("owner", "stars"))
+
+	assert.PanicsWithValue(t, "Incorrect type for column. Need int64, got string", func() {
+		sqb.FromCTE[any](open).ColumnEquals("stars", "five")
+	})
+
+	assert.Panics(t, func() {
+		sqb.FromCTE[any](open).ColumnEquals("status", "open")
+	})
+
+	assert.PanicsWithValue(t, "Recursive CTE tree: incorrect type for column owner. Need string, got int64", func() {
+		sqb.NewRecursiveCTE("tree",
+			sqb.NewTable[any]("orders", sqb.Psql(), &exampleOrderModel{}).Select("owner"),
+			func(self *sqb.Table[any]) sqb.Selectable {
+				return sqb.NewTable[any]("orders", sqb.Psql(), &exampleOrderModel{}).Select("stars")
+			},
+		)
+	})
+}
@@ -0,0 +1,167 @@
+package sqb
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+/*
+	Columns may use any type which the driver can scan and bind. Besides the types database/sql supports
+	natively, this includes any type implementing sql.Scanner and driver.Valuer, such as uuid or decimal
+	types, and third party types registered with RegisterType. Registered types are adapted automatically, so
+	they can be used as receivers, filter values and written values without wrappers.
+*/
+
+// Converts between a type and its database representation, for types which do not implement sql.Scanner
+// and driver.Valuer themselves.
+type TypeAdaptor[T any] struct {
+	// Scan src, as returned by the driver, into dest. src is nil for NULL
+	Scan func(src any, dest *T) error
+	// Convert v to a value the driver can bind
+	Value func(v T) (driver.Value, error)
+}
+
+type registeredType struct {
+	scan  func(src any, dest any) error
+	value func(v any) (driver.Value, error)
+}
+
+var (
+	registeredTypesMu sync.RWMutex
+	registeredTypes   = map[reflect.Type]registeredType{}
+)
+
+// Register an adaptor for a type which cannot implement sql.Scanner and driver.Valuer, e.g. a type from
+// another package. Intended to be called during program initialization, registering a type twice panics.
+func RegisterType[T any](adaptor TypeAdaptor[T]) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+
+	if adaptor.Scan == nil || adaptor.Value == nil {
+		panic(fmt.Sprintf("RegisterType: %s must provide both Scan and Value", t))
+	}
+
+	registeredTypesMu.Lock()
+	defer registeredTypesMu.Unlock()
+
+	if _, ok := registeredTypes[t]; ok {
+		panic(fmt.Sprintf("RegisterType: %s is already registered", t))
+	}
+
+	registeredTypes[t] = registeredType{
+		scan: func(src any, dest any) error {
+			return adaptor.Scan(src, dest.(*T))
+		},
+		value: func(v any) (driver.Value, error) {
+			return adaptor.Value(v.(T))
+		},
+	}
+}
+
+func lookupRegisteredType(t reflect.Type) (registeredType, bool) {
+	registeredTypesMu.RLock()
+	defer registeredTypesMu.RUnlock()
+
+	r, ok := registeredTypes[t]
+	return r, ok
+}
+
+// Scans into a receiver of a registered type
+type registeredScanner struct {
+	dest any
+	scan func(src any, dest any) error
+}
+
+func (s *registeredScanner) Scan(src any) error {
+	return s.scan(src, s.dest)
+}
+
+// Binds a value of a registered type. Holds only the value so that equal values remain comparable.
+type registeredValuer struct {
+	v any
+}
+
+func (r registeredValuer) Value() (driver.Value, error) {
+	adaptor, ok := lookupRegisteredType(reflect.TypeOf(r.v))
+	if !ok {
+		return nil, fmt.Errorf("no adaptor registered for %T", r.v)
+	}
+
+	return adaptor.value(r.v)
+}
+
+// Adapt v to a value the driver can bind, the counterpart of scanTarget: registered types bind through their
+// adaptor, and slices other than []byte bind as arrays unless they implement driver.Valuer.
+func bindValue(v any) any {
+	if v == nil {
+		return v
+	}
+
+	t := reflect.TypeOf(v)
+	if _, ok := lookupRegisteredType(t); ok {
+		return registeredValuer{v: v}
+	}
+
+	if _, ok := v.(driver.Valuer); !ok && t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8 {
+		return pq.Array(v)
+	}
+
+	return v
+}
+
+var (
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	valuerType  = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+	timeType    = reflect.TypeOf(time.Time{})
+)
+
+// Whether the driver can scan into a pointer to t, possibly after adapting it with scanTarget
+func canScan(t reflect.Type) bool {
+	if reflect.PointerTo(t).Implements(scannerType) || t == timeType {
+		return true
+	}
+
+	if _, ok := lookupRegisteredType(t); ok {
+		return true
+	}
+
+	switch {
+	case t.Kind() == reflect.Interface:
+		return t.NumMethod() == 0
+	case t.Kind() == reflect.Ptr:
+		return canScan(t.Elem())
+	case t.Kind() == reflect.Slice:
+		return t.Elem().Kind() == reflect.Uint8 || canScan(t.Elem())
+	}
+
+	return isBasicKind(t.Kind())
+}
+
+// Whether the driver can bind a value of type t
+func canBind(t reflect.Type) bool {
+	if t.Implements(valuerType) || t == timeType {
+		return true
+	}
+
+	if _, ok := lookupRegisteredType(t); ok {
+		return true
+	}
+
+	switch {
+	case t.Kind() == reflect.Ptr:
+		return canBind(t.Elem())
+	case t.Kind() == reflect.Slice:
+		return t.Elem().Kind() == reflect.Uint8
+	}
+
+	return isBasicKind(t.Kind())
+}
+
+func isBasicKind(k reflect.Kind) bool {
+	return k == reflect.Bool || k == reflect.String || isSignedInt(k) || isUnsignedInt(k) || isFloat(k)
+}
@@ -0,0 +1,129 @@
+package sqb_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	sqb "github.com/themanciraptor/SQb"
+	"github.com/themanciraptor/SQb/internal/fakedb"
+)
+
+// A custom type implementing sql.Scanner and driver.Valuer
+type exampleMoney struct {
+	Cents int64
+}
+
+func (m *exampleMoney) Scan(src any) error {
+	var dollars, cents int64
+	_, err := fmt.Sscanf(src.(string), "$%d.%02d", &dollars, &cents)
+	m.Cents = dollars*100 + cents
+	return err
+}
+
+func (m exampleMoney) Value() (driver.Value, error) {
+	return fmt.Sprintf("$%d.%02d", m.Cents/100, m.Cents%100), nil
+}
+
+// A slice type which scans itself, rather than through pq.Array
+type exampleCSV []string
+
+func (c *exampleCSV) Scan(src any) error {
+	*c = strings.Split(src.(string), ",")
+	return nil
+}
+
+func (c exampleCSV) Value() (driver.Value, error) {
+	return strings.Join(c, ","), nil
+}
+
+// A type standing in for a third party type, which cannot implement sql.Scanner itself
+type exampleColor struct {
+	R, G, B uint8
+}
+
+// A type which the driver cannot scan or bind
+type exampleUnscannable struct {
+	Value int
+}
+
+type exampleCustomModel struct {
+	Price  exampleMoney       `psql:"price"`
+	Tags   exampleCSV         `psql:"tags"`
+	Colour exampleColor       `psql:"colour"`
+	Other  exampleUnscannable `psql:"other"`
+}
+
+type exampleCustomResult struct {
+	Price  exampleMoney
+	Tags   exampleCSV
+	Colour exampleColor
+}
+
+func init() {
+	sqb.RegisterType(sqb.TypeAdaptor[exampleColor]{
+		Scan: func(src any, dest *exampleColor) error {
+			_, err := fmt.Sscanf(src.(string), "#%02x%02x%02x", &dest.R, &dest.G, &dest.B)
+			return err
+		},
+		Value: func(v exampleColor) (driver.Value, error) {
+			return fmt.Sprintf("#%02x%02x%02x", v.R, v.G, v.B), nil
+		},
+	})
+}
+
+func Test_CustomTypes_ScanAndBindWithoutWrappers(t *testing.T) {
+	var gotArgs []driver.Value
+	db, _ := fakedb.New(func(query string, args []driver.Value) ([]string, [][]driver.Value, error) {
+		gotArgs = args
+		return []string{"colour", "price", "tags"}, [][]driver.Value{{"#ff8000", "$12.34", "a,b"}}, nil
+	})
+
+	acc := sqb.NewAccumulator(func(r *exampleCustomResult) map[string]interface{} {
+		return map[string]interface{}{
+			"price":  &r.Price,
+			"tags":   &r.Tags,
+			"colour": &r.Colour,
+		}
+	})
+
+	q := sqb.NewTable[exampleCustomResult]("things", sqb.Psql(), &exampleCustomModel{}).
+		LoadReceiversFromAccumulator(acc).
+		ColumnEquals("price", exampleMoney{Cents: 500}).
+		ColumnEquals("colour", exampleColor{R: 1, G: 2, B: 3}).
+		Build(acc, sqb.Psql())
+
+	assert.NoError(t, q.Run(context.Background(), sqb.NewPreparedRunner(db, 1)))
+	assert.Equal(t, []driver.Value{"$5.00", "#010203"}, gotArgs)
+	assert.Equal(t, []exampleCustomResult{
+		{
+			Price:  exampleMoney{Cents: 1234},
+			Tags:   exampleCSV{"a", "b"},
+			Colour: exampleColor{R: 255, G: 128},
+		},
+	}, acc.GetResults())
+}
+
+func Test_CustomTypes_RejectUnscannableTypes(t *testing.T) {
+	tt := sqb.NewTable[exampleCustomResult]("things", sqb.Psql(), &exampleCustomModel{})
+
+	assert.PanicsWithValue(t, "SetField: cannot scan to sqb_test.exampleUnscannable, implement sql.Scanner or use RegisterType", func() {
+		tt.SetColumnReceiver("other", new(exampleUnscannable))
+	})
+
+	assert.PanicsWithValue(t, "Cannot bind sqb_test.exampleUnscannable, implement driver.Valuer or use RegisterType", func() {
+		tt.ColumnEquals("other", exampleUnscannable{})
+	})
+}
+
+func Test_RegisterType_PanicsWhenRegisteredTwice(t *testing.T) {
+	assert.PanicsWithValue(t, "RegisterType: sqb_test.exampleColor is already registered", func() {
+		sqb.RegisterType(sqb.TypeAdaptor[exampleColor]{
+			Scan:  func(src any, dest *exampleColor) error { return nil },
+			Value: func(v exampleColor) (driver.Value, error) { return nil, nil },
+		})
+	})
+}
@@ -0,0 +1,168 @@
+package sqb
+
+import (
+	"fmt"
+	"strings"
+)
+
+/*
+	Tables can write the DDL for their model, for test fixtures and local development. Column types come
+	from the dialect, which must implement DDLDialect, or from the type tag option. Columns are NOT NULL
+	unless their field can hold NULL, e.g. a Null, pointer or slice, and indexes are declared with the index
+	and unique tag options. See tags.go.
+*/
+
+type DDLOption func(*ddlOptions)
+
+type ddlOptions struct {
+	ifNotExists bool
+	ifExists    bool
+}
+
+// Write CREATE TABLE IF NOT EXISTS and CREATE INDEX IF NOT EXISTS
+func IfNotExists() DDLOption {
+	return func(o *ddlOptions) {
+		o.ifNotExists = true
+	}
+}
+
+// Write DROP TABLE IF EXISTS
+func IfExists() DDLOption {
+	return func(o *ddlOptions) {
+		o.ifExists = true
+	}
+}
+
+// Create the table and its indexes. Statements are separated by semicolons.
+func (t *Table[T]) CreateTableSQL(dialect Dialect, options ...DDLOption) string {
+	ddl, ok := DialectAs[DDLDialect](dialect)
+	if !ok {
+		panic(fmt.Sprintf("CreateTableSQL: dialect %T cannot write DDL, implement DDLDialect", dialect))
+	}
+
+	o := applyDDLOptions(options)
+	guard := ""
+	if o.ifNotExists {
+		guard = "IF NOT EXISTS "
+	}
+
+	definitions := []string{}
+	for _, column := range t.Columns() {
+		definitions = append(definitions, column.definition(ddl, t.tableName))
+	}
+
+	if pk := t.PrimaryKey(); len(pk) > 0 {
+		definitions = append(definitions, fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(pk, ", ")))
+	}
+
+	statements := []string{fmt.Sprintf("CREATE TABLE %s%s (\n\t%s\n)", guard, t.tableName, strings.Join(definitions, ",\n\t"))}
+
+	for _, index := range t.sqlIndexes() {
+		unique := ""
+		if index.unique {
+			unique = "UNIQUE "
+		}
+
+		statements = append(statements, fmt.Sprintf("CREATE %sINDEX %s%s ON %s (%s)", unique, guard, index.name, t.tableName, strings.Join(index.columns, ", ")))
+	}
+
+	return strings.Join(statements, ";\n") + ";"
+}
+
+// Drop the table, which also drops its indexes
+func (t *Table[T]) DropTableSQL(options ...DDLOption) string {
+	if applyDDLOptions(options).ifExists {
+		return fmt.Sprintf("DROP TABLE IF EXISTS %s;", t.tableName)
+	}
+
+	return fmt.Sprintf("DROP TABLE %s;", t.tableName)
+}
+
+func applyDDLOptions(options []DDLOption) ddlOptions {
+	o := ddlOptions{}
+	for _, option := range options {
+		option(&o)
+	}
+
+	return o
+}
+
+func (s *Column) definition(ddl DDLDialect, tableName string) string {
+	sqlType := s.sqlType
+	if sqlType == "" {
+		var ok bool
+		if sqlType, ok = ddl.ColumnType(unwrapNullable(s.typ)); !ok {
+			panic(fmt.Sprintf("CreateTableSQL: no SQL type for column %s of %s with type %s, set one with the type tag option", s.name, tableName, s.typ))
+		}
+	}
+
+	parts := []string{s.name, sqlType}
+
+	if !typeHoldsNull(s.typ) || s.primaryKey {
+		parts = append(parts, "NOT NULL")
+	}
+
+	switch {
+	case s.defaultValue != "":
+		parts = append(parts, "DEFAULT "+s.defaultValue)
+	case s.hasDefault && s.primaryKey && isSignedInt(unwrapNullable(s.typ).Kind()):
+		parts = append(parts, ddl.IdentityClause())
+	case s.hasDefault:
+		panic(fmt.Sprintf("CreateTableSQL: column %s of %s has a default but no expression, set one with default=<expression>", s.name, tableName))
+	}
+
+	if len(s.enum) > 0 {
+		values := make([]string, 0, len(s.enum))
+		for _, value := range s.enum {
+			values = append(values, "'"+strings.ReplaceAll(value, "'", "''")+"'")
+		}
+
+		parts = append(parts, fmt.Sprintf("CHECK (%s IN (%s))", s.name, strings.Join(values, ", ")))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+type tableIndex struct {
+	name    string
+	unique  bool
+	columns []string
+}
+
+// The table's indexes, in the order their first column appears in the model
+func (t *Table[T]) sqlIndexes() []*tableIndex {
+	var indexes []*tableIndex
+	named := map[string]*tableIndex{}
+
+	for _, column := range t.Columns() {
+		for _, option := range column.sqlIndexes {
+			if option.name == "" {
+				suffix := "idx"
+				if option.unique {
+					suffix = "key"
+				}
+
+				indexes = append(indexes, &tableIndex{
+					name:    fmt.Sprintf("%s_%s_%s", t.tableName, column.name, suffix),
+					unique:  option.unique,
+					columns: []string{column.name},
+				})
+
+				continue
+			}
+
+			index, ok := named[option.name]
+			if !ok {
+				index = &tableIndex{name: option.name, unique: option.unique}
+				named[option.name] = index
+				indexes = append(indexes, index)
+			} else if index.unique != option.unique {
+				panic(fmt.Sprintf("CreateTableSQL: index %s of %s is declared both unique and not unique", option.name, t.tableName))
+			}
+
+			index.columns = append(index.columns, column.name)
+		}
+	}
+
+	return indexes
+}
@@ -0,0 +1,116 @@
+package sqb_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	sqb "github.com/themanciraptor/SQb"
+)
+
+type exampleAccountModel struct {
+	ID         int64                       `psql:"id,pk,default"`
+	Email      string                      `psql:"email,unique"`
+	OrgID      int32                       `psql:"org_id,index=accounts_org_idx"`
+	Handle     string                      `psql:"handle,index=accounts_org_idx"`
+	Status     string                      `psql:"status,enum=active|it's closed,default='active'"`
+	Nickname   sqb.Null[string]            `psql:"nickname"`
+	Tags       []string                    `psql:"tags"`
+	Settings   sqb.JSON[map[string]string] `psql:"settings"`
+	Avatar     []byte                      `psql:"avatar"`
+	ExternalID exampleUUID                 `psql:"external_id,type=uuid,index"`
+	CreatedAt  time.Time                   `psql:"created_at,readonly,default=now()"`
+	DeletedAt  *time.Time                  `psql:"deleted_at"`
+	Score      float64                     `psql:"score"`
+}
+
+func Test_CreateTableSQL_BuildsCorrectly(t *testing.T) {
+	tt := sqb.NewTable[any]("accounts", sqb.Psql(), &exampleAccountModel{})
+
+	expected := `CREATE TABLE accounts (
+	id bigint NOT NULL GENERATED BY DEFAULT AS IDENTITY,
+	email text NOT NULL,
+	org_id integer NOT NULL,
+	handle text NOT NULL,
+	status text NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'it''s closed')),
+	nickname text,
+	tags text[],
+	settings jsonb,
+	avatar bytea,
+	external_id uuid NOT NULL,
+	created_at timestamp with time zone NOT NULL DEFAULT now(),
+	deleted_at timestamp with time zone,
+	score double precision NOT NULL,
+	PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX accounts_email_key ON accounts (email);
+CREATE INDEX accounts_org_idx ON accounts (org_id, handle);
+CREATE INDEX accounts_external_id_idx ON accounts (external_id);`
+
+	assert.Equal(t, expected, tt.CreateTableSQL(sqb.Psql()))
+	assert.Equal(t, "DROP TABLE accounts;", tt.DropTableSQL())
+}
+
+func Test_CreateTableSQL_IfNotExists(t *testing.T) {
+	tt := sqb.NewTable[any]("users", sqb.InlineLimit(sqb.Psql()), &exampleUserModel{})
+
+	expected := `CREATE TABLE IF NOT EXISTS users (
+	id bigint NOT NULL GENERATED BY DEFAULT AS IDENTITY,
+	email text NOT NULL,
+	nickname text NOT NULL,
+	created_at timestamp with time zone NOT NULL DEFAULT now(),
+	PRIMARY KEY (id)
+);`
+
+	assert.Equal(t, expected, tt.CreateTableSQL(sqb.InlineLimit(sqb.Psql()), sqb.IfNotExists()))
+	assert.Equal(t, "DROP TABLE IF EXISTS users;", tt.DropTableSQL(sqb.IfExists()))
+	assert.Equal(t, "DROP TABLE users;", tt.DropTableSQL(sqb.IfNotExists()))
+	assert.Contains(t, tt.CreateTableSQL(sqb.Psql(), sqb.IfExists()), "CREATE TABLE users (")
+}
+
+func Test_CreateTableSQL_ScannersAreNotNull(t *testing.T) {
+	type pricedModel struct {
+		Price    exampleMoney    `psql:"price,type=text"`
+		Priority examplePriority `psql:"priority"`
+		Discount *exampleMoney   `psql:"discount,type=text"`
+	}
+
+	expected := `CREATE TABLE prices (
+	price text NOT NULL,
+	priority text NOT NULL CHECK (priority IN ('low', 'high')),
+	discount text
+);`
+
+	assert.Equal(t, expected, sqb.NewTable[any]("prices", sqb.Psql(), &pricedModel{}).CreateTableSQL(sqb.Psql()))
+}
+
+func Test_CreateTableSQL_Panics(t *testing.T) {
+	type scannerModel struct {
+		ID exampleUUID `psql:"id"`
+	}
+
+	assert.PanicsWithValue(t, "CreateTableSQL: no SQL type for column id of things with type sqb_test.exampleUUID, set one with the type tag option", func() {
+		sqb.NewTable[any]("things", sqb.Psql(), &scannerModel{}).CreateTableSQL(sqb.Psql())
+	})
+
+	type conflictingModel struct {
+		A string `psql:"a,index=things_idx"`
+		B string `psql:"b,unique=things_idx"`
+	}
+
+	assert.PanicsWithValue(t, "CreateTableSQL: index things_idx of things is declared both unique and not unique", func() {
+		sqb.NewTable[any]("things", sqb.Psql(), &conflictingModel{}).CreateTableSQL(sqb.Psql())
+	})
+
+	type bareDefaultModel struct {
+		Code string `psql:"code,default"`
+	}
+
+	assert.PanicsWithValue(t, "CreateTableSQL: column code of things has a default but no expression, set one with default=<expression>", func() {
+		sqb.NewTable[any]("things", sqb.Psql(), &bareDefaultModel{}).CreateTableSQL(sqb.Psql())
+	})
+
+	assert.PanicsWithValue(t, "CreateTableSQL: dialect sqb_test.exampleDialect cannot write DDL, implement DDLDialect", func() {
+		sqb.NewTable[any]("users", sqb.Psql(), &exampleUserModel{}).CreateTableSQL(exampleDialect{})
+	})
+}
@@ -0,0 +1,228 @@
+package sqb
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+	DebugString renders a query with its params written into the SQL as literals, so that it can be pasted
+	into a database shell. The output is for logs and debugging only: it must never be executed by the
+	application, as the params are no longer bound.
+*/
+
+// Dialects which write values as SQL literals in their own syntax implement LiteralFormatter. Other
+// dialects use standard SQL literals.
+type LiteralFormatter interface {
+	FormatLiteral(v driver.Value) string
+}
+
+type DebugOption func(*debugOptions)
+
+type debugOptions struct {
+	pretty bool
+}
+
+// Start each clause of the query on a new line
+func Pretty() DebugOption {
+	return func(o *debugOptions) {
+		o.pretty = true
+	}
+}
+
+func (q *Query[T]) DebugString(options ...DebugOption) string {
+	o := debugOptions{}
+	for _, option := range options {
+		option(&o)
+	}
+
+	dialect := q.dialect
+	if dialect == nil {
+		dialect = Psql()
+	}
+
+	formatLiteral := standardLiteral
+	if formatter, ok := DialectAs[LiteralFormatter](dialect); ok {
+		formatLiteral = formatter.FormatLiteral
+	}
+
+	literals := make([]string, 0, len(q.params))
+	for _, param := range q.params {
+		v, err := driver.DefaultParameterConverter.ConvertValue(param)
+		if err != nil {
+			literals = append(literals, fmt.Sprintf("/* %v */", err))
+			continue
+		}
+
+		literals = append(literals, formatLiteral(v))
+	}
+
+	rendered := inlineParams(q.query, dialect, literals)
+	if o.pretty {
+		rendered = prettyPrint(rendered)
+	}
+
+	return rendered
+}
+
+// Write a value as a standard SQL literal
+func standardLiteral(v driver.Value) string {
+	switch v := v.(type) {
+	case nil:
+		return "NULL"
+	case bool:
+		if v {
+			return "TRUE"
+		}
+
+		return "FALSE"
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case string:
+		return quoteLiteral(v)
+	case []byte:
+		return "X'" + hex.EncodeToString(v) + "'"
+	case time.Time:
+		return "TIMESTAMP " + quoteLiteral(v.Format("2006-01-02 15:04:05.999999999Z07:00"))
+	default:
+		return quoteLiteral(fmt.Sprint(v))
+	}
+}
+
+func quoteLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// Replace each placeholder outside of quotes with its param's literal. Dialects whose placeholders are
+// numbered, e.g. $1, are matched by number, others by position.
+func inlineParams(query string, dialect Dialect, literals []string) string {
+	if len(literals) == 0 {
+		return query
+	}
+
+	positional := dialect.FormatParam(1) == dialect.FormatParam(2)
+	placeholders := make([]string, len(literals))
+	for i := range literals {
+		placeholders[i] = dialect.FormatParam(i + 1)
+	}
+
+	var b strings.Builder
+	next := 0
+
+	for i := 0; i < len(query); {
+		if c := query[i]; c == '\'' || c == '"' {
+			end := closingQuote(query, i)
+			b.WriteString(query[i:end])
+			i = end
+			continue
+		}
+
+		if positional {
+			if next < len(literals) && strings.HasPrefix(query[i:], placeholders[0]) {
+				b.WriteString(literals[next])
+				i += len(placeholders[0])
+				next++
+				continue
+			}
+		} else if n := matchPlaceholder(query[i:], placeholders); n >= 0 {
+			b.WriteString(literals[n])
+			i += len(placeholders[n])
+			continue
+		}
+
+		b.WriteByte(query[i])
+		i++
+	}
+
+	return b.String()
+}
+
+// The index of the longest placeholder prefixing s, so that $12 is not read as $1, or -1
+func matchPlaceholder(s string, placeholders []string) int {
+	match := -1
+
+	for n, placeholder := range placeholders {
+		if strings.HasPrefix(s, placeholder) && (match < 0 || len(placeholder) > len(placeholders[match])) {
+			match = n
+		}
+	}
+
+	return match
+}
+
+// The offset after the quote closing the one opening at start. Doubled quotes are escapes.
+func closingQuote(s string, start int) int {
+	quote := s[start]
+
+	for i := start + 1; i < len(s); i++ {
+		if s[i] != quote {
+			continue
+		}
+
+		if i+1 < len(s) && s[i+1] == quote {
+			i++
+			continue
+		}
+
+		return i + 1
+	}
+
+	return len(s)
+}
+
+// Keywords starting a new clause, longest first so that LEFT JOIN is not split
+var clauseKeywords = []string{
+	"ON CONFLICT", "ORDER BY", "GROUP BY", "UNION ALL", "LEFT JOIN", "RIGHT JOIN", "INNER JOIN", "FULL JOIN",
+	"CROSS JOIN", "RETURNING", "INTERSECT", "EXCEPT", "SELECT", "VALUES", "HAVING", "UNION", "WHERE", "LIMIT",
+	"FROM", "JOIN",
+}
+
+// Break the query before each top level clause. Clauses within parentheses, such as subqueries, stay on
+// one line.
+func prettyPrint(query string) string {
+	var b strings.Builder
+	depth := 0
+
+	for i := 0; i < len(query); {
+		c := query[i]
+
+		switch {
+		case c == '\'' || c == '"':
+			end := closingQuote(query, i)
+			b.WriteString(query[i:end])
+			i = end
+			continue
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == ' ' && depth == 0:
+			if keyword := clauseKeyword(query[i+1:]); keyword != "" {
+				b.WriteString("\n" + keyword)
+				i += 1 + len(keyword)
+				continue
+			}
+		}
+
+		b.WriteByte(c)
+		i++
+	}
+
+	return b.String()
+}
+
+func clauseKeyword(s string) string {
+	for _, keyword := range clauseKeywords {
+		if strings.HasPrefix(s, keyword) && (len(s) == len(keyword) || s[len(keyword)] == ' ') {
+			return keyword
+		}
+	}
+
+	return ""
+}
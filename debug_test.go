@@ -0,0 +1,75 @@
+package sqb_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	sqb "github.com/themanciraptor/SQb"
+)
+
+func Test_DebugString_InlinesParams(t *testing.T) {
+	acc := NewResultAccumulator()
+	created := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+
+	q := sqb.NewTable[exampleResult]("exampleTable", sqb.Psql(), &exampleModel{}).
+		Select("cool").
+		ColumnEquals("cool", "it's a 'quote' $1").
+		ColumnEquals("created_time", created).
+		ColumnIn("number_of_star", int64(1), int64(2), int64(3), int64(4), int64(5), int64(6), int64(7), int64(8), int64(9)).
+		ColumnArrayContains("loves", []string{"doom", "gloom"}).
+		ColumnEquals("is_true_true", true).
+		Limit(10, 0).
+		LoadReceiversFromAccumulator(acc).
+		Build(acc, sqb.Psql())
+
+	expected := "SELECT cool FROM exampleTable WHERE (cool = 'it''s a ''quote'' $1' AND created_time = '2024-05-01 12:30:00Z'::timestamptz AND number_of_star IN (1, 2, 3, 4, 5, 6, 7, 8, 9) AND loves @> '{\"doom\",\"gloom\"}' AND is_true_true = TRUE) LIMIT 10 OFFSET 0"
+
+	assert.Equal(t, expected, q.DebugString())
+}
+
+func Test_DebugString_WritesNullAndBytes(t *testing.T) {
+	type blobModel struct {
+		ID       int64            `psql:"id"`
+		Data     []byte           `psql:"data"`
+		Nickname sqb.Null[string] `psql:"nickname"`
+	}
+
+	q := sqb.NewTable[any]("blobs", sqb.Psql(), &blobModel{}).Insert(blobModel{ID: 1, Data: []byte{0xde, 0xad}}).Build(sqb.Psql())
+
+	assert.Equal(t, `INSERT INTO blobs (id, data, nickname) VALUES (1, '\xdead'::bytea, NULL)`, q.DebugString())
+}
+
+func Test_DebugString_PositionalParams(t *testing.T) {
+	acc := NewResultAccumulator()
+
+	q := sqb.NewTable[exampleResult]("exampleTable", exampleDialect{}, &exampleModel{}).
+		Select("cool").
+		ColumnEquals("cool", "why?").
+		ColumnEquals("number_of_star", int64(3)).
+		LoadReceiversFromAccumulator(acc).
+		Build(acc, exampleDialect{})
+
+	assert.Equal(t, "SELECT cool FROM exampleTable WHERE (cool = 'why?' AND number_of_star = 3)", q.DebugString())
+}
+
+func Test_DebugString_Pretty(t *testing.T) {
+	acc := NewResultAccumulator()
+	orders := sqb.NewTable[any]("orders", sqb.Psql(), &exampleOrderModel{}).Select("owner").ColumnEquals("status", "open")
+
+	q := sqb.NewTable[exampleResult]("exampleTable", sqb.Psql(), &exampleModel{}).
+		Select("cool").
+		ColumnInSubquery("cool", orders).
+		AddOrderByClause("cool", sqb.Descending).
+		Limit(5, 10).
+		LoadReceiversFromAccumulator(acc).
+		Build(acc, sqb.Psql())
+
+	expected := `SELECT cool
+FROM exampleTable
+WHERE cool IN (SELECT owner FROM orders WHERE status = 'open')
+ORDER BY cool DESC
+LIMIT 5 OFFSET 10`
+
+	assert.Equal(t, expected, q.DebugString(sqb.Pretty()))
+}
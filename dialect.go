@@ -1,6 +1,13 @@
 package sqb
 
-import "fmt"
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
 
 // Any variance in dialects should be accounted for here.
 type Dialect interface {
@@ -8,6 +15,90 @@ type Dialect interface {
 	FormatParam(n int) string
 }
 
+// Optional features which are not available in every dialect
+type Feature int
+
+const (
+	// The json operators ->, ->>, @> and ?
+	JSONOperators Feature = iota
+	// The array operators @>, <@, && and ANY
+	ArrayOperators
+)
+
+func (f Feature) String() string {
+	switch f {
+	case JSONOperators:
+		return "json operators"
+	case ArrayOperators:
+		return "array operators"
+	default:
+		return fmt.Sprintf("feature %d", int(f))
+	}
+}
+
+// Dialects which lack some optional features implement FeatureSupporter. Other dialects support the features
+// psql does.
+type FeatureSupporter interface {
+	// Whether the dialect supports an optional feature. Queries using unsupported features fail to build.
+	Supports(f Feature) bool
+}
+
+// Whether the dialect supports an optional feature
+func supports(dialect Dialect, f Feature) bool {
+	if supporter, ok := DialectAs[FeatureSupporter](dialect); ok {
+		return supporter.Supports(f)
+	}
+
+	return psql{}.Supports(f)
+}
+
+// Dialects which cannot bind LIMIT and OFFSET values as params implement LimitBinder, see InlineLimit. Other
+// dialects bind them, as psql does.
+type LimitBinder interface {
+	// Whether LIMIT and OFFSET values may be bound as params rather than written into the query.
+	BindLimit() bool
+}
+
+// Whether the dialect binds LIMIT and OFFSET values as params
+func bindsLimit(dialect Dialect) bool {
+	if binder, ok := DialectAs[LimitBinder](dialect); ok {
+		return binder.BindLimit()
+	}
+
+	return true
+}
+
+// Dialects which can describe the columns of the database's tables implement ColumnsQuerier, see Verify.
+type ColumnsQuerier interface {
+	// Build a query selecting the table name, as given in tableNames, column name, data type, array element
+	// type and nullability ("YES" or "NO") of each column of the named tables. Names are resolved as the
+	// database resolves them in queries.
+	ColumnsQuery(params *ParamList, tableNames []string) string
+}
+
+// Dialects which can write DDL implement DDLDialect, see Table.CreateTableSQL.
+type DDLDialect interface {
+	// The SQL type of columns holding values of the Go type t, or false if there is none
+	ColumnType(t reflect.Type) (string, bool)
+
+	// The clause declaring an integer column whose values the database generates, e.g. for primary keys
+	// tagged default without an expression
+	IdentityClause() string
+}
+
+// Dialects with session level advisory locks implement AdvisoryLocker. The migrate package takes one so
+// that concurrent migrations are serialised.
+type AdvisoryLocker interface {
+	LockQuery(params *ParamList, key int64) string
+	UnlockQuery(params *ParamList, key int64) string
+}
+
+// Dialects which can show a query's plan implement Explainer, see SlowQueryHook.
+type Explainer interface {
+	// A statement returning the plan of query as a single JSON value, without running the query
+	ExplainQuery(query string) string
+}
+
 type psql struct{}
 
 func (p psql) StructTag() string {
@@ -18,6 +109,135 @@ func (p psql) FormatParam(n int) string {
 	return fmt.Sprintf("$%d", n)
 }
 
+func (p psql) BindLimit() bool {
+	return true
+}
+
+func (p psql) Supports(f Feature) bool {
+	switch f {
+	case JSONOperators, ArrayOperators:
+		return true
+	default:
+		return false
+	}
+}
+
+func (p psql) ColumnsQuery(params *ParamList, tableNames []string) string {
+	values := make([]string, 0, len(tableNames))
+	for _, tableName := range tableNames {
+		values = append(values, fmt.Sprintf("(%s::text)", params.RecordValueAndReturnParam(tableName)))
+	}
+
+	// to_regclass resolves unqualified names through the search path, as queries do
+	return fmt.Sprintf("SELECT t.name, c.column_name, c.data_type, c.udt_name, c.is_nullable FROM (VALUES %s) AS t(name) "+
+		"JOIN pg_class r ON r.oid = to_regclass(t.name) JOIN pg_namespace n ON n.oid = r.relnamespace "+
+		"JOIN information_schema.columns c ON c.table_schema = n.nspname AND c.table_name = r.relname "+
+		"ORDER BY t.name, c.ordinal_position", strings.Join(values, ", "))
+}
+
+var psqlTypes = map[reflect.Kind]string{
+	reflect.Bool:    "boolean",
+	reflect.Int8:    "smallint",
+	reflect.Int16:   "smallint",
+	reflect.Uint8:   "smallint",
+	reflect.Int32:   "integer",
+	reflect.Uint16:  "integer",
+	reflect.Int:     "bigint",
+	reflect.Int64:   "bigint",
+	reflect.Uint32:  "bigint",
+	reflect.Uint:    "numeric(20)",
+	reflect.Uint64:  "numeric(20)",
+	reflect.Float32: "real",
+	reflect.Float64: "double precision",
+	reflect.String:  "text",
+}
+
+func (p psql) ColumnType(t reflect.Type) (string, bool) {
+	switch {
+	case t == timeType:
+		return "timestamp with time zone", true
+	case isJSONType(t):
+		return "jsonb", true
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+		return "bytea", true
+	case t.Kind() == reflect.Slice:
+		element, ok := p.ColumnType(t.Elem())
+		return element + "[]", ok
+	case reflect.PointerTo(t).Implements(scannerType):
+		// Scanners decide what they accept, so they need an explicit type
+		return "", false
+	}
+
+	sqlType, ok := psqlTypes[t.Kind()]
+	return sqlType, ok
+}
+
+func (p psql) IdentityClause() string {
+	return "GENERATED BY DEFAULT AS IDENTITY"
+}
+
+func (p psql) LockQuery(params *ParamList, key int64) string {
+	return fmt.Sprintf("SELECT pg_advisory_lock(%s)", params.RecordValueAndReturnParam(key))
+}
+
+func (p psql) UnlockQuery(params *ParamList, key int64) string {
+	return fmt.Sprintf("SELECT pg_advisory_unlock(%s)", params.RecordValueAndReturnParam(key))
+}
+
+func (p psql) ExplainQuery(query string) string {
+	return "EXPLAIN (FORMAT JSON) " + query
+}
+
+// Bytes and times are cast, so the literal has the param's type
+func (p psql) FormatLiteral(v driver.Value) string {
+	switch v := v.(type) {
+	case []byte:
+		return `'\x` + hex.EncodeToString(v) + "'::bytea"
+	case time.Time:
+		return quoteLiteral(v.Format("2006-01-02 15:04:05.999999999Z07:00")) + "::timestamptz"
+	default:
+		return standardLiteral(v)
+	}
+}
+
 func Psql() Dialect {
 	return psql{}
 }
+
+type inlineLimit struct {
+	Dialect
+}
+
+func (i inlineLimit) BindLimit() bool {
+	return false
+}
+
+func (i inlineLimit) Unwrap() Dialect {
+	return i.Dialect
+}
+
+// Wraps a dialect so that LIMIT and OFFSET values are written into the query instead of being bound as
+// params. Useful for drivers or poolers which do not accept placeholders in a LIMIT clause.
+func InlineLimit(d Dialect) Dialect {
+	return inlineLimit{Dialect: d}
+}
+
+// Look through dialect wrappers, such as InlineLimit, for a dialect implementing the optional interface I,
+// e.g. DialectAs[DDLDialect](d)
+func DialectAs[I any](dialect Dialect) (I, bool) {
+	for dialect != nil {
+		if i, ok := dialect.(I); ok {
+			return i, true
+		}
+
+		wrapper, ok := dialect.(interface{ Unwrap() Dialect })
+		if !ok {
+			break
+		}
+
+		dialect = wrapper.Unwrap()
+	}
+
+	var zero I
+	return zero, false
+}
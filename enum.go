@@ -0,0 +1,131 @@
+package sqb
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+/*
+	Enum columns only hold a fixed set of values. They are declared with a tag option, e.g.
+	`psql:"status,enum=open|closed|pending"`, or by registering the Go type of the column with RegisterEnum.
+	Filtering an enum column by any other value fails to build, rather than running a query which can never
+	match, and scanning any other value fails.
+*/
+
+var (
+	enumsMu sync.RWMutex
+	enums   = map[reflect.Type][]string{}
+)
+
+// Register the values allowed for columns of type T. Intended to be called during program initialization.
+func RegisterEnum[T any](values ...T) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+
+	allowed := make([]string, 0, len(values))
+	for _, v := range values {
+		allowed = append(allowed, fmt.Sprint(v))
+	}
+
+	enumsMu.Lock()
+	defer enumsMu.Unlock()
+
+	if _, ok := enums[t]; ok {
+		panic(fmt.Sprintf("RegisterEnum: %s is already registered", t))
+	}
+
+	enums[t] = allowed
+}
+
+func lookupEnum(t reflect.Type) []string {
+	enumsMu.RLock()
+	defer enumsMu.RUnlock()
+
+	return enums[t]
+}
+
+// Returns an error if v is not one of the column's allowed values. NULL is always allowed.
+func (s *Column) checkEnumValue(v any) error {
+	if len(s.enum) == 0 || v == nil {
+		return nil
+	}
+
+	if _, ok := v.(*NamedParam); ok {
+		return nil
+	}
+
+	// Pointers are bound as the value they point to, and nil pointers as NULL
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+
+		rv = rv.Elem()
+	}
+
+	v = rv.Interface()
+
+	if valuer, ok := v.(driver.Valuer); ok {
+		dv, err := valuer.Value()
+		if err != nil || dv == nil {
+			return err
+		}
+
+		v = dv
+	}
+
+	value := fmt.Sprint(v)
+	if src, ok := v.([]byte); ok {
+		value = string(src)
+	}
+
+	for _, allowed := range s.enum {
+		if value == allowed {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("column %s: value %q is not one of %s", s.name, value, strings.Join(s.enum, "|"))
+}
+
+// Validates values before scanning them into an enum column's receiver
+type enumScanner struct {
+	column *Column
+	dest   any
+}
+
+func (e *enumScanner) Scan(src any) error {
+	if err := e.column.checkEnumValue(src); err != nil {
+		return err
+	}
+
+	if s, ok := e.dest.(sql.Scanner); ok {
+		return s.Scan(src)
+	}
+
+	// Enums are strings or integers, which are all the driver can return for them
+	dest := reflect.ValueOf(e.dest).Elem()
+	switch v := src.(type) {
+	case string:
+		if dest.Kind() == reflect.String {
+			dest.SetString(v)
+			return nil
+		}
+	case []byte:
+		if dest.Kind() == reflect.String {
+			dest.SetString(string(v))
+			return nil
+		}
+	case int64:
+		if isSignedInt(dest.Kind()) {
+			dest.SetInt(v)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("column %s: cannot scan %T into %s", e.column.name, src, dest.Type())
+}
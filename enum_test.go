@@ -0,0 +1,177 @@
+package sqb_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	sqb "github.com/themanciraptor/SQb"
+	"github.com/themanciraptor/SQb/internal/fakedb"
+)
+
+type examplePriority string
+
+func init() {
+	sqb.RegisterEnum[examplePriority]("low", "high")
+}
+
+type exampleTicketModel struct {
+	ID       int64           `psql:"id"`
+	Status   string          `psql:"status,enum=open|closed|pending"`
+	Priority examplePriority `psql:"priority"`
+}
+
+type exampleTicket struct {
+	Status   string
+	Priority examplePriority
+}
+
+func ticketAccumulator() sqb.Accumulator[exampleTicket] {
+	return sqb.NewAccumulator(func(r *exampleTicket) map[string]interface{} {
+		return map[string]interface{}{
+			"status":   &r.Status,
+			"priority": sqb.NewNull(&r.Priority),
+		}
+	})
+}
+
+func Test_Enum_FiltersBuildWithAllowedValues(t *testing.T) {
+	acc := ticketAccumulator()
+	q, err := sqb.NewTable[exampleTicket]("tickets", sqb.Psql(), &exampleTicketModel{}).
+		LoadReceiversFromAccumulator(acc).
+		ColumnIn("status", "open", "pending").
+		ColumnEquals("priority", examplePriority("high")).
+		TryBuild(acc, sqb.Psql())
+
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT priority, status FROM tickets WHERE (status IN ($1, $2) AND priority = $3)", q.GetQuery())
+	assert.Equal(t, []interface{}{"open", "pending", examplePriority("high")}, q.GetParams())
+}
+
+func Test_Enum_FiltersFailToBuildWithOtherValues(t *testing.T) {
+	type testCase struct {
+		description string
+		build       func(tt *sqb.Table[exampleTicket])
+		errMsg      string
+	}
+
+	testCases := []testCase{
+		{
+			description: "equals a value outside a tagged enum",
+			build:       func(tt *sqb.Table[exampleTicket]) { tt.ColumnEquals("status", "archived") },
+			errMsg:      `Build: column status: value "archived" is not one of open|closed|pending`,
+		},
+		{
+			description: "in a value outside a tagged enum",
+			build:       func(tt *sqb.Table[exampleTicket]) { tt.ColumnIn("status", "open", "archived") },
+			errMsg:      `Build: column status: value "archived" is not one of open|closed|pending`,
+		},
+		{
+			description: "equals a value outside a registered enum",
+			build:       func(tt *sqb.Table[exampleTicket]) { tt.ColumnEquals("priority", examplePriority("urgent")) },
+			errMsg:      `Build: column priority: value "urgent" is not one of low|high`,
+		},
+		{
+			description: "equals a pointer to a value outside a tagged enum",
+			build: func(tt *sqb.Table[exampleTicket]) {
+				status := "archived"
+				tt.ColumnEquals("status", &status)
+			},
+			errMsg: `Build: column status: value "archived" is not one of open|closed|pending`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			acc := ticketAccumulator()
+			tt := sqb.NewTable[exampleTicket]("tickets", sqb.Psql(), &exampleTicketModel{}).LoadReceiversFromAccumulator(acc)
+
+			tc.build(tt)
+			_, err := tt.TryBuild(acc, sqb.Psql())
+
+			assert.EqualError(t, err, tc.errMsg)
+
+			params := sqb.NewParamList(sqb.Psql())
+			tt.BuildFilter(params)
+			assert.EqualError(t, params.Err(), strings.TrimPrefix(tc.errMsg, "Build: "))
+		})
+	}
+}
+
+func Test_Enum_FiltersAcceptPointers(t *testing.T) {
+	status := "open"
+	var priority *examplePriority
+
+	acc := ticketAccumulator()
+	q, err := sqb.NewTable[exampleTicket]("tickets", sqb.Psql(), &exampleTicketModel{}).
+		LoadReceiversFromAccumulator(acc).
+		ColumnEquals("status", &status).
+		ColumnEquals("priority", priority).
+		TryBuild(acc, sqb.Psql())
+
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT priority, status FROM tickets WHERE (status = $1 AND priority = $2)", q.GetQuery())
+}
+
+func Test_Enum_ScanningValidatesValues(t *testing.T) {
+	type testCase struct {
+		description string
+		row         []driver.Value
+		errMsg      string
+	}
+
+	testCases := []testCase{
+		{
+			description: "allowed values and NULL scan",
+			row:         []driver.Value{nil, "closed"},
+		},
+		{
+			description: "a value outside a tagged enum fails",
+			row:         []driver.Value{"low", "archived"},
+			errMsg:      `column status: value "archived" is not one of open|closed|pending`,
+		},
+		{
+			description: "a value outside a registered enum fails",
+			row:         []driver.Value{[]byte("urgent"), "open"},
+			errMsg:      `column priority: value "urgent" is not one of low|high`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			db, _ := fakedb.New(func(query string, args []driver.Value) ([]string, [][]driver.Value, error) {
+				return []string{"priority", "status"}, [][]driver.Value{tc.row}, nil
+			})
+
+			acc := ticketAccumulator()
+			q := sqb.NewTable[exampleTicket]("tickets", sqb.Psql(), &exampleTicketModel{}).
+				LoadReceiversFromAccumulator(acc).
+				Build(acc, sqb.Psql())
+
+			err := q.Run(context.Background(), sqb.NewPreparedRunner(db, 1))
+
+			if tc.errMsg == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, []exampleTicket{{Status: "closed"}}, acc.GetResults())
+			} else {
+				assert.ErrorContains(t, err, tc.errMsg)
+			}
+		})
+	}
+}
+
+func Test_Enum_TemplateBindValidatesValues(t *testing.T) {
+	acc := ticketAccumulator()
+	tmpl := sqb.NewTable[exampleTicket]("tickets", sqb.Psql(), &exampleTicketModel{}).
+		LoadReceiversFromAccumulator(acc).
+		ColumnEquals("status", sqb.Param[string]("status")).
+		Compile(sqb.Psql())
+
+	_, err := tmpl.Bind(map[string]any{"status": "open"})
+	assert.NoError(t, err)
+
+	_, err = tmpl.Bind(map[string]any{"status": "archived"})
+	assert.EqualError(t, err, `Template: column status: value "archived" is not one of open|closed|pending`)
+}
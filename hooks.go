@@ -0,0 +1,252 @@
+package sqb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+/*
+	Hooks observe queries run through a runner, for logging, metrics and tracing. Wrap a runner with
+	WithHooks: each hook's BeforeQuery is called before a query runs, and its AfterQuery once its rows are
+	closed, in reverse order. Hooks fire for every query the HookedRunner runs, including through types
+	embedding it. Query.Run passes the query's table, statement and dialect along in the context. Hooks
+	receive the number of params rather than their values, so that user input is not logged by accident.
+*/
+
+type QueryEvent struct {
+	Query      string
+	ParamCount int
+
+	// The table the query is built from and its statement, e.g. SELECT or INSERT
+	Table     string
+	Statement string
+
+	// Set before AfterQuery is called
+	Start    time.Time
+	Duration time.Duration
+	Rows     int
+	Err      error
+
+	// Let hooks such as SlowQueryHook run statements about the query
+	params  []interface{}
+	dialect Dialect
+	runner  tempDriver
+}
+
+type QueryHook interface {
+	// Called before the query runs. The returned context is used to run the query and is passed to
+	// AfterQuery, e.g. to carry a span.
+	BeforeQuery(ctx context.Context, event *QueryEvent) context.Context
+	AfterQuery(ctx context.Context, event *QueryEvent)
+}
+
+// A runner whose queries are observed by hooks
+type HookedRunner struct {
+	runner tempDriver
+	hooks  []QueryHook
+}
+
+// Wrap runner so that queries run through it call hooks. Wrapping a HookedRunner adds to its hooks.
+func WithHooks(runner tempDriver, hooks ...QueryHook) *HookedRunner {
+	if hooked, ok := runner.(*HookedRunner); ok {
+		return &HookedRunner{runner: hooked.runner, hooks: append(append([]QueryHook{}, hooked.hooks...), hooks...)}
+	}
+
+	return &HookedRunner{runner: runner, hooks: hooks}
+}
+
+func (h *HookedRunner) RunQuery(ctx context.Context, query string, params []interface{}) (tempRows, func(ctx context.Context), error) {
+	info, _ := ctx.Value(queryInfoKey{}).(queryInfo)
+	event := &QueryEvent{
+		Query:      query,
+		ParamCount: len(params),
+		Table:      info.table,
+		Statement:  info.statement,
+		params:     params,
+		dialect:    info.dialect,
+		runner:     h.runner,
+	}
+
+	for _, hook := range h.hooks {
+		ctx = hook.BeforeQuery(ctx, event)
+	}
+
+	event.Start = time.Now()
+	res, closer, err := h.runner.RunQuery(ctx, query, params)
+	if err != nil {
+		event.Err = err
+		h.afterQuery(ctx, event)
+		return nil, nil, err
+	}
+
+	rows := &hookedRows{rows: res, event: event}
+
+	return rows, func(closeCtx context.Context) {
+		// Errors ending iteration are only reported by the rows, e.g. sql.Rows.Err
+		if errRows, ok := res.(interface{ Err() error }); ok && event.Err == nil {
+			event.Err = errRows.Err()
+		}
+
+		closer(closeCtx)
+		h.afterQuery(ctx, event)
+	}, nil
+}
+
+func (h *HookedRunner) afterQuery(ctx context.Context, event *QueryEvent) {
+	event.Duration = time.Since(event.Start)
+	for i := len(h.hooks) - 1; i >= 0; i-- {
+		h.hooks[i].AfterQuery(ctx, event)
+	}
+}
+
+// Counts scanned rows and records scan errors for AfterQuery
+type hookedRows struct {
+	rows  tempRows
+	event *QueryEvent
+}
+
+func (r *hookedRows) Next() bool {
+	return r.rows.Next()
+}
+
+func (r *hookedRows) Scan(dest ...interface{}) error {
+	err := r.rows.Scan(dest...)
+	if err != nil {
+		r.event.Err = errors.Join(r.event.Err, err)
+		return err
+	}
+
+	r.event.Rows++
+	return nil
+}
+
+// Describes a query to hooks, e.g. exampleTable and SELECT
+type queryInfo struct {
+	table     string
+	statement string
+	dialect   Dialect
+}
+
+type queryInfoKey struct{}
+
+func withQueryInfo(ctx context.Context, info queryInfo) context.Context {
+	return context.WithValue(ctx, queryInfoKey{}, info)
+}
+
+// Logs each query with slog. Successful queries are logged at level, failed queries at slog.LevelError.
+type SlogHook struct {
+	logger *slog.Logger
+	level  slog.Level
+}
+
+func NewSlogHook(logger *slog.Logger, level slog.Level) *SlogHook {
+	return &SlogHook{logger: logger, level: level}
+}
+
+func (h *SlogHook) BeforeQuery(ctx context.Context, event *QueryEvent) context.Context {
+	return ctx
+}
+
+func (h *SlogHook) AfterQuery(ctx context.Context, event *QueryEvent) {
+	level := h.level
+	attrs := []slog.Attr{
+		slog.String("sql", event.Query),
+		slog.Int("params", event.ParamCount),
+		slog.String("table", event.Table),
+		slog.String("statement", event.Statement),
+		slog.Duration("duration", event.Duration),
+		slog.Int("rows", event.Rows),
+	}
+
+	if event.Err != nil {
+		level = slog.LevelError
+		attrs = append(attrs, slog.String("error", event.Err.Error()))
+	}
+
+	h.logger.LogAttrs(ctx, level, "query", attrs...)
+}
+
+// A span attribute, named by the OpenTelemetry database semantic conventions
+type SpanAttribute struct {
+	Key   string
+	Value any
+}
+
+// The subset of a tracer which SpanHook needs. An OpenTelemetry trace.Tracer can be adapted by starting a
+// client span and converting the attributes with attribute.String and attribute.Int.
+type SpanTracer interface {
+	StartSpan(ctx context.Context, name string, attributes ...SpanAttribute) (context.Context, Span)
+}
+
+type Span interface {
+	SetAttributes(attributes ...SpanAttribute)
+	// Record the error as an event on the span, e.g. with trace.Span.RecordError
+	RecordError(err error)
+	// Mark the span as failed, e.g. with trace.Span.SetStatus(codes.Error, description)
+	SetErrorStatus(description string)
+	End()
+}
+
+// Traces each query as a span following the OpenTelemetry database semantic conventions
+type SpanHook struct {
+	tracer SpanTracer
+	system string
+}
+
+type spanKey struct{}
+
+// Create a hook starting spans with tracer. system is the db.system.name attribute, e.g. postgresql.
+func NewSpanHook(tracer SpanTracer, system string) *SpanHook {
+	return &SpanHook{tracer: tracer, system: system}
+}
+
+func (h *SpanHook) BeforeQuery(ctx context.Context, event *QueryEvent) context.Context {
+	// Spans are named "{operation} {collection}" by the conventions
+	name := event.Statement
+	if event.Table != "" {
+		name += " " + event.Table
+	}
+
+	ctx, span := h.tracer.StartSpan(ctx, name,
+		SpanAttribute{Key: "db.system.name", Value: h.system},
+		SpanAttribute{Key: "db.operation.name", Value: event.Statement},
+		SpanAttribute{Key: "db.collection.name", Value: event.Table},
+		SpanAttribute{Key: "db.query.text", Value: event.Query},
+	)
+
+	return context.WithValue(ctx, spanKey{}, span)
+}
+
+func (h *SpanHook) AfterQuery(ctx context.Context, event *QueryEvent) {
+	span, ok := ctx.Value(spanKey{}).(Span)
+	if !ok {
+		return
+	}
+
+	span.SetAttributes(SpanAttribute{Key: "db.response.returned_rows", Value: event.Rows})
+
+	if event.Err != nil {
+		span.SetAttributes(errorAttributes(event.Err)...)
+		span.RecordError(event.Err)
+		span.SetErrorStatus(event.Err.Error())
+	}
+
+	span.End()
+}
+
+// error.type is the database's status code when the driver reports one, e.g. a postgres SQLSTATE, and
+// the error's type otherwise
+func errorAttributes(err error) []SpanAttribute {
+	var coded interface{ SQLState() string }
+	if errors.As(err, &coded) {
+		return []SpanAttribute{
+			{Key: "db.response.status_code", Value: coded.SQLState()},
+			{Key: "error.type", Value: coded.SQLState()},
+		}
+	}
+
+	return []SpanAttribute{{Key: "error.type", Value: fmt.Sprintf("%T", err)}}
+}
@@ -0,0 +1,202 @@
+package sqb_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	sqb "github.com/themanciraptor/SQb"
+	"github.com/themanciraptor/SQb/internal/fakedb"
+)
+
+// An in-memory tracer recording finished spans
+type exampleTracer struct {
+	spans []*exampleSpan
+}
+
+type exampleSpan struct {
+	name       string
+	attributes map[string]any
+	err        error
+	status     string
+	ended      bool
+}
+
+func (t *exampleTracer) StartSpan(ctx context.Context, name string, attributes ...sqb.SpanAttribute) (context.Context, sqb.Span) {
+	span := &exampleSpan{name: name, attributes: map[string]any{}}
+	span.SetAttributes(attributes...)
+	t.spans = append(t.spans, span)
+
+	return ctx, span
+}
+
+func (s *exampleSpan) SetAttributes(attributes ...sqb.SpanAttribute) {
+	for _, attribute := range attributes {
+		s.attributes[attribute.Key] = attribute.Value
+	}
+}
+
+func (s *exampleSpan) RecordError(err error) {
+	s.err = err
+}
+
+func (s *exampleSpan) SetErrorStatus(description string) {
+	s.status = description
+}
+
+func (s *exampleSpan) End() {
+	s.ended = true
+}
+
+// Records the order hooks are called in
+type exampleOrderHook struct {
+	name  string
+	calls *[]string
+}
+
+func (h exampleOrderHook) BeforeQuery(ctx context.Context, event *sqb.QueryEvent) context.Context {
+	*h.calls = append(*h.calls, "before "+h.name)
+	return ctx
+}
+
+func (h exampleOrderHook) AfterQuery(ctx context.Context, event *sqb.QueryEvent) {
+	*h.calls = append(*h.calls, "after "+h.name)
+}
+
+func buildExampleQuery() (*sqb.Query[exampleResult], *exampleResultAccumulator) {
+	acc := NewResultAccumulator()
+	q := sqb.NewTable[exampleResult]("exampleTable", sqb.Psql(), &exampleModel{}).
+		Select("cool").
+		ColumnEquals("number_of_star", int64(3)).
+		LoadReceiversFromAccumulator(acc).
+		Build(acc, sqb.Psql())
+
+	return q, acc
+}
+
+func Test_Hooks_ObserveQueries(t *testing.T) {
+	db, _ := fakedb.New(func(query string, args []driver.Value) ([]string, [][]driver.Value, error) {
+		return []string{"cool"}, [][]driver.Value{{"doom"}, {"gloom"}}, nil
+	})
+
+	var logs bytes.Buffer
+	tracer := &exampleTracer{}
+	calls := []string{}
+
+	runner := sqb.WithHooks(sqb.NewPreparedRunner(db, 1),
+		sqb.NewSlogHook(slog.New(slog.NewJSONHandler(&logs, nil)), slog.LevelInfo),
+		exampleOrderHook{name: "a", calls: &calls},
+	)
+	runner = sqb.WithHooks(runner, sqb.NewSpanHook(tracer, "postgresql"), exampleOrderHook{name: "b", calls: &calls})
+
+	q, acc := buildExampleQuery()
+	assert.NoError(t, q.Run(context.Background(), runner))
+	assert.Len(t, acc.GetResults(), 2)
+
+	assert.Equal(t, []string{"before a", "before b", "after b", "after a"}, calls)
+
+	var logged map[string]any
+	assert.NoError(t, json.Unmarshal(logs.Bytes(), &logged))
+	assert.Equal(t, "INFO", logged["level"])
+	assert.Equal(t, "query", logged["msg"])
+	assert.Equal(t, "SELECT cool FROM exampleTable WHERE number_of_star = $1", logged["sql"])
+	assert.Equal(t, float64(1), logged["params"])
+	assert.Equal(t, "exampleTable", logged["table"])
+	assert.Equal(t, "SELECT", logged["statement"])
+	assert.Equal(t, float64(2), logged["rows"])
+	assert.Contains(t, logged, "duration")
+
+	assert.Len(t, tracer.spans, 1)
+	span := tracer.spans[0]
+	assert.Equal(t, "SELECT exampleTable", span.name)
+	assert.True(t, span.ended)
+	assert.NoError(t, span.err)
+	assert.Equal(t, map[string]any{
+		"db.system.name":            "postgresql",
+		"db.operation.name":         "SELECT",
+		"db.collection.name":        "exampleTable",
+		"db.query.text":             "SELECT cool FROM exampleTable WHERE number_of_star = $1",
+		"db.response.returned_rows": 2,
+	}, span.attributes)
+}
+
+func Test_Hooks_ReceiveErrors(t *testing.T) {
+	db, _ := fakedb.New(func(query string, args []driver.Value) ([]string, [][]driver.Value, error) {
+		return nil, nil, errors.New("relation does not exist")
+	})
+
+	var logs bytes.Buffer
+	tracer := &exampleTracer{}
+	runner := sqb.WithHooks(sqb.NewPreparedRunner(db, 1), sqb.NewSlogHook(slog.New(slog.NewJSONHandler(&logs, nil)), slog.LevelDebug), sqb.NewSpanHook(tracer, "postgresql"))
+
+	q, _ := buildExampleQuery()
+	err := q.Run(context.Background(), runner)
+	assert.ErrorContains(t, err, "relation does not exist")
+
+	var logged map[string]any
+	assert.NoError(t, json.Unmarshal(logs.Bytes(), &logged))
+	assert.Equal(t, "ERROR", logged["level"])
+	assert.Contains(t, logged["error"], "relation does not exist")
+
+	span := tracer.spans[0]
+	assert.ErrorContains(t, span.err, "relation does not exist")
+	assert.Equal(t, "relation does not exist", span.status)
+	assert.Equal(t, "*errors.errorString", span.attributes["error.type"])
+	assert.True(t, span.ended)
+}
+
+func Test_Hooks_ReportSQLState(t *testing.T) {
+	db, _ := fakedb.New(func(query string, args []driver.Value) ([]string, [][]driver.Value, error) {
+		return nil, nil, &pq.Error{Code: "42P01", Message: "relation does not exist"}
+	})
+
+	tracer := &exampleTracer{}
+	q, _ := buildExampleQuery()
+	assert.Error(t, q.Run(context.Background(), sqb.WithHooks(sqb.NewPreparedRunner(db, 1), sqb.NewSpanHook(tracer, "postgresql"))))
+
+	span := tracer.spans[0]
+	assert.Equal(t, "42P01", span.attributes["error.type"])
+	assert.Equal(t, "42P01", span.attributes["db.response.status_code"])
+	assert.Equal(t, "pq: relation does not exist", span.status)
+}
+
+// Runners wrapping a HookedRunner still fire its hooks
+type exampleEmbeddingRunner struct {
+	*sqb.HookedRunner
+}
+
+func Test_Hooks_FireThroughEmbeddingRunners(t *testing.T) {
+	db, _ := fakedb.New(func(query string, args []driver.Value) ([]string, [][]driver.Value, error) {
+		return []string{"cool"}, [][]driver.Value{{"doom"}}, nil
+	})
+
+	tracer := &exampleTracer{}
+	runner := exampleEmbeddingRunner{sqb.WithHooks(sqb.NewPreparedRunner(db, 1), sqb.NewSpanHook(tracer, "postgresql"))}
+
+	q, acc := buildExampleQuery()
+	assert.NoError(t, q.Run(context.Background(), runner))
+	assert.Len(t, acc.GetResults(), 1)
+
+	assert.Len(t, tracer.spans, 1)
+	assert.Equal(t, "SELECT exampleTable", tracer.spans[0].name)
+	assert.Equal(t, 1, tracer.spans[0].attributes["db.response.returned_rows"])
+	assert.True(t, tracer.spans[0].ended)
+}
+
+func Test_Hooks_DescribeInserts(t *testing.T) {
+	db, _ := fakedb.New(func(query string, args []driver.Value) ([]string, [][]driver.Value, error) {
+		return nil, nil, nil
+	})
+
+	tracer := &exampleTracer{}
+	q := sqb.NewTable[any]("users", sqb.Psql(), &exampleUserModel{}).Insert(exampleUserModel{Email: "a@b.c"}).Build(sqb.Psql())
+
+	assert.NoError(t, q.Run(context.Background(), sqb.WithHooks(sqb.NewPreparedRunner(db, 1), sqb.NewSpanHook(tracer, "postgresql"))))
+	assert.Equal(t, "INSERT users", tracer.spans[0].name)
+}
@@ -0,0 +1,171 @@
+/*
+Package fakedb is a minimal database/sql driver so that runners and migrations can be tested without a
+database. Every statement is answered by the handler, which receives the query and its args, and
+statements and transactions are logged in order.
+*/
+package fakedb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+type Handler func(query string, args []driver.Value) (columns []string, rows [][]driver.Value, err error)
+
+type DB struct {
+	handler Handler
+
+	prepares atomic.Int64
+	closes   atomic.Int64
+
+	mu  sync.Mutex
+	log []string
+}
+
+// Open a database answering every statement with handler. A nil handler answers with no rows.
+func New(handler Handler) (*sql.DB, *DB) {
+	f := &DB{handler: handler}
+	return sql.OpenDB(f), f
+}
+
+// The statements run, with BEGIN, COMMIT and ROLLBACK for transactions
+func (f *DB) Executed() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]string{}, f.log...)
+}
+
+// The number of statements prepared
+func (f *DB) Prepares() int64 {
+	return f.prepares.Load()
+}
+
+// The number of prepared statements closed
+func (f *DB) Closes() int64 {
+	return f.closes.Load()
+}
+
+func (f *DB) record(entry string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.log = append(f.log, entry)
+}
+
+func (f *DB) Connect(context.Context) (driver.Conn, error) {
+	return &fakeConn{db: f}, nil
+}
+
+func (f *DB) Driver() driver.Driver {
+	return fakeDriver{db: f}
+}
+
+type fakeDriver struct {
+	db *DB
+}
+
+func (d fakeDriver) Open(string) (driver.Conn, error) {
+	return &fakeConn{db: d.db}, nil
+}
+
+type fakeConn struct {
+	db *DB
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	c.db.prepares.Add(1)
+	return &fakeStmt{db: c.db, query: query}, nil
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	c.db.record("BEGIN")
+	return fakeTx{db: c.db}, nil
+}
+
+type fakeTx struct {
+	db *DB
+}
+
+func (t fakeTx) Commit() error {
+	t.db.record("COMMIT")
+	return nil
+}
+
+func (t fakeTx) Rollback() error {
+	t.db.record("ROLLBACK")
+	return nil
+}
+
+type fakeStmt struct {
+	db    *DB
+	query string
+}
+
+func (s *fakeStmt) Close() error {
+	s.db.closes.Add(1)
+	return nil
+}
+
+func (s *fakeStmt) NumInput() int {
+	return -1
+}
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	_, _, err := s.run(args)
+	if err != nil {
+		return nil, err
+	}
+
+	return driver.RowsAffected(0), nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	columns, rows, err := s.run(args)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fakeRows{columns: columns, rows: rows}, nil
+}
+
+func (s *fakeStmt) run(args []driver.Value) ([]string, [][]driver.Value, error) {
+	s.db.record(s.query)
+
+	if s.db.handler == nil {
+		return nil, nil, nil
+	}
+
+	return s.db.handler(s.query, args)
+}
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string {
+	return r.columns
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
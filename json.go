@@ -0,0 +1,109 @@
+package sqb
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+/*
+	JSON columns hold json or jsonb values. Declare them in a table model with a JSON field, e.g.
+	Attrs sqb.JSON[map[string]any] `psql:"attrs"`, and scan them into any value encoding/json can decode to.
+	Columns are jsonb unless declared with the type tag option, e.g. type=json, and JSONContains and
+	JSONHasKey need jsonb.
+*/
+
+// JSON scans a json column into its receiver through encoding/json, and binds its receiver as a json param.
+// A NULL column sets the receiver to its zero value.
+//
+// Call NewJSON() to scan into a receiver. A JSON used as a model or result field, without a receiver,
+// holds its value itself: JSONOf makes one to write, and Get returns the scanned value. The zero value
+// holds the zero value of T.
+type JSON[T any] struct {
+	receiver *T
+	v        T
+}
+
+func NewJSON[T any](receiver *T) *JSON[T] {
+	return &JSON[T]{receiver: receiver}
+}
+
+// A JSON holding v, e.g. to set a JSON field of a model being inserted
+func JSONOf[T any](v T) JSON[T] {
+	return JSON[T]{v: v}
+}
+
+// The value held, or the receiver's value
+func (j JSON[T]) Get() T {
+	if j.receiver != nil {
+		return *j.receiver
+	}
+
+	return j.v
+}
+
+func (j *JSON[T]) Scan(value interface{}) error {
+	// Decode into a fresh value, decoding into the receiver would merge with its previous contents
+	var decoded T
+
+	switch v := value.(type) {
+	case nil:
+	case []byte:
+		if err := json.Unmarshal(v, &decoded); err != nil {
+			return err
+		}
+	case string:
+		if err := json.Unmarshal([]byte(v), &decoded); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("JSON: cannot scan %T", value)
+	}
+
+	if j.receiver == nil {
+		j.v = decoded
+		return nil
+	}
+
+	*j.receiver = decoded
+	return nil
+}
+
+// Values are bound as strings, which postgres casts to json or jsonb. Byte slices would be sent as bytea.
+func (j JSON[T]) Value() (driver.Value, error) {
+	b, err := json.Marshal(j.Get())
+	if err != nil {
+		return nil, err
+	}
+
+	return string(b), nil
+}
+
+func (j JSON[T]) jsonValueType() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+type jsonValue interface {
+	jsonValueType() reflect.Type
+}
+
+var (
+	jsonValueIface = reflect.TypeOf((*jsonValue)(nil)).Elem()
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// Whether t holds json, either a JSON wrapper or json.RawMessage
+func isJSONType(t reflect.Type) bool {
+	return t == rawMessageType || (t.Kind() != reflect.Ptr && t.Implements(jsonValueIface))
+}
+
+// Marshal v to be bound as a json param
+func jsonParam(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		panic(fmt.Sprintf("Unable to encode json param: %s", err))
+	}
+
+	return string(b)
+}
@@ -0,0 +1,214 @@
+package sqb_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	sqb "github.com/themanciraptor/SQb"
+)
+
+type exampleAttrs struct {
+	Color string `json:"color"`
+	Size  int    `json:"size"`
+}
+
+type exampleJSONModel struct {
+	Name  string                      `psql:"name"`
+	Attrs sqb.JSON[map[string]any]    `psql:"attrs"`
+	Tags  sqb.JSON[[]string]          `psql:"tags"`
+	Meta  sqb.JSON[exampleAttrs]      `psql:"meta"`
+	Extra sqb.JSON[map[string]string] `psql:"extra"`
+	Log   sqb.JSON[[]string]          `psql:"log,type=json"`
+}
+
+type exampleJSONResult struct {
+	Name  string
+	Attrs exampleAttrs
+}
+
+// A dialect without json operators
+type exampleDialect struct{}
+
+func (exampleDialect) StructTag() string {
+	return "psql"
+}
+
+func (exampleDialect) FormatParam(n int) string {
+	return "?"
+}
+
+func (exampleDialect) Supports(f sqb.Feature) bool {
+	return false
+}
+
+// Implements only the methods every dialect needs
+type minimalDialect struct{}
+
+func (minimalDialect) StructTag() string {
+	return "psql"
+}
+
+func (minimalDialect) FormatParam(n int) string {
+	return "?"
+}
+
+func Test_JSON_ScansAndBinds(t *testing.T) {
+	attrs := exampleAttrs{Color: "stale"}
+	j := sqb.NewJSON(&attrs)
+
+	assert.NoError(t, j.Scan([]byte(`{"size": 3}`)))
+	assert.Equal(t, exampleAttrs{Size: 3}, attrs)
+
+	assert.NoError(t, j.Scan(`{"color": "red"}`))
+	assert.Equal(t, exampleAttrs{Color: "red"}, attrs)
+
+	v, err := j.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, `{"color":"red","size":0}`, v)
+
+	assert.NoError(t, j.Scan(nil))
+	assert.Equal(t, exampleAttrs{}, attrs)
+
+	assert.Error(t, j.Scan(42))
+}
+
+func Test_JSON_HoldsItsValueWithoutAReceiver(t *testing.T) {
+	j := sqb.JSONOf(exampleAttrs{Color: "red"})
+	v, err := j.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, `{"color":"red","size":0}`, v)
+
+	var zero sqb.JSON[exampleAttrs]
+	v, err = zero.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, `{"color":"","size":0}`, v)
+
+	assert.NoError(t, zero.Scan(`{"size": 3}`))
+	assert.Equal(t, exampleAttrs{Size: 3}, zero.Get())
+
+	q := sqb.NewTable[any]("things", sqb.Psql(), &exampleJSONModel{}).
+		Insert(exampleJSONModel{Name: "box", Meta: sqb.JSONOf(exampleAttrs{Color: "red", Size: 2})}).
+		Build(sqb.Psql())
+
+	assert.Contains(t, q.GetParams(), sqb.JSONOf(exampleAttrs{Color: "red", Size: 2}))
+}
+
+func Test_JSON_ReceiversAreTypeChecked(t *testing.T) {
+	r := exampleJSONResult{}
+
+	assert.NotPanics(t, func() {
+		sqb.NewTable[exampleJSONResult]("things", sqb.Psql(), &exampleJSONModel{}).
+			SetColumnReceiver("attrs", sqb.NewJSON(&r.Attrs))
+	})
+
+	assert.Panics(t, func() {
+		sqb.NewTable[exampleJSONResult]("things", sqb.Psql(), &exampleJSONModel{}).
+			SetColumnReceiver("name", sqb.NewJSON(&r.Attrs))
+	})
+}
+
+func Test_JSONFilters_BuildCorrectly(t *testing.T) {
+	type TableFilterBuilder = func(tt *sqb.Table[exampleJSONResult])
+
+	type testCase struct {
+		description    string
+		expectedClause string
+		expectedParams []interface{}
+		TableFilterBuilder
+	}
+
+	testCases := []testCase{
+		{
+			description:    "path equals",
+			expectedClause: "attrs->>$1 = $2",
+			expectedParams: []interface{}{"color", "red"},
+			TableFilterBuilder: func(tt *sqb.Table[exampleJSONResult]) {
+				tt.JSONPathEquals("attrs", []string{"color"}, "red")
+			},
+		},
+		{
+			description:    "nested path equals",
+			expectedClause: "meta->$1->>$2 = $3",
+			expectedParams: []interface{}{"size", "width", "3"},
+			TableFilterBuilder: func(tt *sqb.Table[exampleJSONResult]) {
+				tt.JSONPathEquals("meta", []string{"size", "width"}, "3")
+			},
+		},
+		{
+			description:    "contains",
+			expectedClause: "attrs @> $1",
+			expectedParams: []interface{}{`{"color":"red"}`},
+			TableFilterBuilder: func(tt *sqb.Table[exampleJSONResult]) {
+				tt.JSONContains("attrs", map[string]string{"color": "red"})
+			},
+		},
+		{
+			description:    "has key",
+			expectedClause: "tags ? $1",
+			expectedParams: []interface{}{"color"},
+			TableFilterBuilder: func(tt *sqb.Table[exampleJSONResult]) {
+				tt.JSONHasKey("tags", "color")
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			tt := sqb.NewTable[exampleJSONResult]("things", sqb.Psql(), &exampleJSONModel{})
+			params := sqb.NewParamList(sqb.Psql())
+
+			tc.TableFilterBuilder(tt)
+
+			assert.Equal(t, tc.expectedClause, tt.BuildFilter(params))
+			assert.Equal(t, tc.expectedParams, params.GetParamList())
+		})
+	}
+}
+
+func Test_JSONFilters_FailToBuildWithoutDialectSupport(t *testing.T) {
+	r := exampleJSONResult{}
+	tt := sqb.NewTable[exampleJSONResult]("things", exampleDialect{}, &exampleJSONModel{}).
+		SetColumnReceiver("name", &r.Name).
+		JSONHasKey("attrs", "color")
+
+	acc := sqb.NewAccumulator(func(r *exampleJSONResult) map[string]interface{} { return nil })
+	q, err := tt.TryBuild(acc, exampleDialect{})
+
+	assert.Nil(t, q)
+	assert.EqualError(t, err, fmt.Sprintf("Build: dialect %T does not support json operators", exampleDialect{}))
+
+	assert.Panics(t, func() { tt.Build(acc, exampleDialect{}) })
+}
+
+func Test_JSONFilters_BuildWhenDialectDoesNotSay(t *testing.T) {
+	r := exampleJSONResult{}
+	tt := sqb.NewTable[exampleJSONResult]("things", minimalDialect{}, &exampleJSONModel{}).
+		SetColumnReceiver("name", &r.Name).
+		JSONHasKey("attrs", "color")
+
+	acc := sqb.NewAccumulator(func(r *exampleJSONResult) map[string]interface{} { return nil })
+	q, err := tt.TryBuild(acc, minimalDialect{})
+
+	assert.Nil(t, err)
+	assert.Contains(t, q.GetQuery(), "WHERE attrs ? ?")
+	assert.Equal(t, []interface{}{"color"}, q.GetParams())
+}
+
+func Test_JSONFilters_PanicOnNonJSONColumns(t *testing.T) {
+	assert.PanicsWithValue(t, "Column name is not a json column, got string", func() {
+		sqb.NewTable[exampleJSONResult]("things", sqb.Psql(), &exampleJSONModel{}).JSONHasKey("name", "color")
+	})
+
+	assert.PanicsWithValue(t, "JSONContains: column log is json, the operator needs jsonb", func() {
+		sqb.NewTable[exampleJSONResult]("things", sqb.Psql(), &exampleJSONModel{}).JSONContains("log", []string{"a"})
+	})
+
+	assert.PanicsWithValue(t, "JSONHasKey: column log is json, the operator needs jsonb", func() {
+		sqb.NewTable[exampleJSONResult]("things", sqb.Psql(), &exampleJSONModel{}).JSONHasKey("log", "a")
+	})
+
+	assert.NotPanics(t, func() {
+		sqb.NewTable[exampleJSONResult]("things", sqb.Psql(), &exampleJSONModel{}).JSONPathEquals("log", []string{"0"}, "a")
+	})
+}
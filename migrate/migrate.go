@@ -0,0 +1,418 @@
+/*
+Package migrate applies SQL migrations kept alongside SQb models. Migrations are read from an fs.FS, one
+file per direction:
+
+	0001_create_users.up.sql
+	0001_create_users.down.sql
+
+Files are ordered by their leading version number. Applied versions are recorded in a tracking table,
+schema_migrations unless WithTable is used. Each migration runs in its own transaction together with its
+tracking record, and when the dialect implements sqb.AdvisoryLocker a lock is held while migrating so that
+concurrent deploys apply each migration once.
+*/
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	sqb "github.com/themanciraptor/SQb"
+)
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// A migration and whether it has been applied. Applied migrations missing from the migration files are
+// reported with their recorded name and no SQL.
+type Status struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+type Migrator struct {
+	db         *sql.DB
+	dialect    sqb.Dialect
+	migrations []Migration
+
+	table   string
+	lockKey int64
+}
+
+type Option func(*Migrator)
+
+// Record applied migrations in table instead of schema_migrations
+func WithTable(table string) Option {
+	return func(m *Migrator) {
+		m.table = table
+	}
+}
+
+// Use key for the advisory lock instead of one derived from the tracking table's name
+func WithLockKey(key int64) Option {
+	return func(m *Migrator) {
+		m.lockKey = key
+	}
+}
+
+// Create a migrator for the migrations in the root of migrations. The dialect must implement
+// sqb.DDLDialect, which types the tracking table.
+func New(db *sql.DB, dialect sqb.Dialect, migrations fs.FS, options ...Option) (*Migrator, error) {
+	if _, ok := sqb.DialectAs[sqb.DDLDialect](dialect); !ok {
+		return nil, fmt.Errorf("migrate: dialect %T cannot write DDL, implement sqb.DDLDialect", dialect)
+	}
+
+	loaded, err := Load(migrations)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Migrator{
+		db:         db,
+		dialect:    dialect,
+		migrations: loaded,
+		table:      "schema_migrations",
+	}
+
+	for _, option := range options {
+		option(m)
+	}
+
+	if m.lockKey == 0 {
+		h := fnv.New64a()
+		h.Write([]byte("sqb migrate " + m.table))
+		m.lockKey = int64(h.Sum64())
+	}
+
+	return m, nil
+}
+
+var migrationFile = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// Read migrations from the root of fsys, ordered by version. Other files are ignored.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("migrate: %w", err)
+	}
+
+	byVersion := map[int64]*Migration{}
+
+	for _, entry := range entries {
+		match := migrationFile.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migrate: %s: %w", entry.Name(), err)
+		}
+
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("migrate: %w", err)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		} else if migration.Name != match[2] {
+			return nil, fmt.Errorf("migrate: version %d is used by %s and %s", version, migration.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("migrate: version %d %s has no up migration", migration.Version, migration.Name)
+		}
+
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
+
+// Apply every pending migration in version order, returning the migrations applied. Migrations before the
+// first failure stay applied.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var done []Migration
+
+	err := m.locked(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+
+			params := sqb.NewParamList(m.dialect)
+			record := fmt.Sprintf("INSERT INTO %s (version, name) VALUES (%s, %s)", m.table,
+				params.AppendValueAndReturnParam(migration.Version),
+				params.AppendValueAndReturnParam(migration.Name),
+			)
+
+			if err := m.apply(ctx, conn, migration.Up, record, params.GetParamList()); err != nil {
+				return fmt.Errorf("migrate: up %d %s: %w", migration.Version, migration.Name, err)
+			}
+
+			done = append(done, migration)
+		}
+
+		return nil
+	})
+
+	return done, err
+}
+
+// Roll back the latest steps applied migrations, newest first, returning the migrations rolled back. steps
+// must be at least 1.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	if steps < 1 {
+		return nil, fmt.Errorf("migrate: down needs at least 1 step, got %d", steps)
+	}
+
+	var done []Migration
+
+	err := m.locked(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		versions := make([]int64, 0, len(applied))
+		for version := range applied {
+			versions = append(versions, version)
+		}
+
+		sort.Slice(versions, func(i, j int) bool { return versions[i] > versions[j] })
+
+		for _, version := range versions[:min(steps, len(versions))] {
+			migration, ok := m.migration(version)
+			if !ok {
+				return fmt.Errorf("migrate: down %d %s: no migration file", version, applied[version].name)
+			}
+
+			if migration.Down == "" {
+				return fmt.Errorf("migrate: down %d %s: no down migration", version, migration.Name)
+			}
+
+			params := sqb.NewParamList(m.dialect)
+			record := fmt.Sprintf("DELETE FROM %s WHERE version = %s", m.table, params.AppendValueAndReturnParam(version))
+
+			if err := m.apply(ctx, conn, migration.Down, record, params.GetParamList()); err != nil {
+				return fmt.Errorf("migrate: down %d %s: %w", version, migration.Name, err)
+			}
+
+			done = append(done, migration)
+		}
+
+		return nil
+	})
+
+	return done, err
+}
+
+// Report every migration, in version order, and whether it has been applied. Status only reads: when the
+// dialect implements sqb.ColumnsQuerier and the tracking table doesn't exist yet, every migration is pending.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("migrate: %w", err)
+	}
+	defer conn.Close()
+
+	applied := map[int64]appliedRecord{}
+
+	exists, err := m.tableExists(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	if exists {
+		if applied, err = m.applied(ctx, conn); err != nil {
+			return nil, err
+		}
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := Status{Migration: migration}
+		if record, ok := applied[migration.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = record.appliedAt
+			delete(applied, migration.Version)
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	for version, record := range applied {
+		statuses = append(statuses, Status{
+			Migration: Migration{Version: version, Name: record.name},
+			Applied:   true,
+			AppliedAt: record.appliedAt,
+		})
+	}
+
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+
+	return statuses, nil
+}
+
+func (m *Migrator) migration(version int64) (Migration, bool) {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return migration, true
+		}
+	}
+
+	return Migration{}, false
+}
+
+// Run fn on a single connection, holding the advisory lock if the dialect has one
+func (m *Migrator) locked(ctx context.Context, fn func(conn *sql.Conn) error) (err error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("migrate: %w", err)
+	}
+	defer conn.Close()
+
+	if locker, ok := sqb.DialectAs[sqb.AdvisoryLocker](m.dialect); ok {
+		params := sqb.NewParamList(m.dialect)
+		if _, err := conn.ExecContext(ctx, locker.LockQuery(params, m.lockKey), params.GetParamList()...); err != nil {
+			return fmt.Errorf("migrate: failed to lock: %w", err)
+		}
+
+		defer func() {
+			params := sqb.NewParamList(m.dialect)
+			if _, unlockErr := conn.ExecContext(context.WithoutCancel(ctx), locker.UnlockQuery(params, m.lockKey), params.GetParamList()...); unlockErr != nil {
+				err = errors.Join(err, fmt.Errorf("migrate: failed to unlock: %w", unlockErr))
+			}
+		}()
+	}
+
+	if err := m.createTable(ctx, conn); err != nil {
+		return err
+	}
+
+	return fn(conn)
+}
+
+func (m *Migrator) createTable(ctx context.Context, conn *sql.Conn) error {
+	ddl, _ := sqb.DialectAs[sqb.DDLDialect](m.dialect)
+
+	columnType := func(v any) string {
+		sqlType, _ := ddl.ColumnType(reflect.TypeOf(v))
+		return sqlType
+	}
+
+	query := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (version %s NOT NULL, name %s NOT NULL, applied_at %s NOT NULL DEFAULT CURRENT_TIMESTAMP, PRIMARY KEY (version))",
+		m.table, columnType(int64(0)), columnType(""), columnType(time.Time{}))
+
+	if _, err := conn.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("migrate: failed to create %s: %w", m.table, err)
+	}
+
+	return nil
+}
+
+// Whether the tracking table exists, assumed when the dialect cannot describe tables
+func (m *Migrator) tableExists(ctx context.Context, conn *sql.Conn) (bool, error) {
+	querier, ok := sqb.DialectAs[sqb.ColumnsQuerier](m.dialect)
+	if !ok {
+		return true, nil
+	}
+
+	params := sqb.NewParamList(m.dialect)
+	rows, err := conn.QueryContext(ctx, querier.ColumnsQuery(params, []string{m.table}), params.GetParamList()...)
+	if err != nil {
+		return false, fmt.Errorf("migrate: failed to look up %s: %w", m.table, err)
+	}
+	defer rows.Close()
+
+	exists := rows.Next()
+	if err := rows.Err(); err != nil {
+		return false, fmt.Errorf("migrate: failed to look up %s: %w", m.table, err)
+	}
+
+	return exists, nil
+}
+
+type appliedRecord struct {
+	name      string
+	appliedAt time.Time
+}
+
+func (m *Migrator) applied(ctx context.Context, conn *sql.Conn) (map[int64]appliedRecord, error) {
+	rows, err := conn.QueryContext(ctx, fmt.Sprintf("SELECT version, name, applied_at FROM %s", m.table))
+	if err != nil {
+		return nil, fmt.Errorf("migrate: failed to read %s: %w", m.table, err)
+	}
+	defer rows.Close()
+
+	applied := map[int64]appliedRecord{}
+	for rows.Next() {
+		var version int64
+		var record appliedRecord
+
+		if err := rows.Scan(&version, &record.name, &record.appliedAt); err != nil {
+			return nil, fmt.Errorf("migrate: failed to read %s: %w", m.table, err)
+		}
+
+		applied[version] = record
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("migrate: failed to read %s: %w", m.table, err)
+	}
+
+	return applied, nil
+}
+
+// Run a migration's SQL and update the tracking table in one transaction
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, migration string, record string, params []interface{}) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(migration) != "" {
+		if _, err := tx.ExecContext(ctx, migration); err != nil {
+			return errors.Join(err, tx.Rollback())
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, record, params...); err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,206 @@
+package migrate_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	sqb "github.com/themanciraptor/SQb"
+	"github.com/themanciraptor/SQb/internal/fakedb"
+	"github.com/themanciraptor/SQb/migrate"
+)
+
+var exampleMigrations = fstest.MapFS{
+	"0001_create_users.up.sql":   {Data: []byte("CREATE TABLE users (id bigint)")},
+	"0001_create_users.down.sql": {Data: []byte("DROP TABLE users")},
+	"0002_add_email.up.sql":      {Data: []byte("ALTER TABLE users ADD COLUMN email text")},
+	"0002_add_email.down.sql":    {Data: []byte("ALTER TABLE users DROP COLUMN email")},
+	"0010_create_orders.up.sql":  {Data: []byte("CREATE TABLE orders (id bigint)")},
+	"README.md":                  {Data: []byte("not a migration")},
+}
+
+var appliedAt = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+// Emulates the tracking table, failing any statement containing failOn
+type fakeTracking struct {
+	applied map[int64]string
+	failOn  string
+	missing bool
+}
+
+func (f *fakeTracking) handle(query string, args []driver.Value) ([]string, [][]driver.Value, error) {
+	switch {
+	case f.failOn != "" && strings.Contains(query, f.failOn):
+		return nil, nil, errors.New("syntax error")
+	case strings.HasPrefix(query, "SELECT t.name, c.column_name"):
+		if f.missing {
+			return []string{"name", "column_name", "data_type", "udt_name", "is_nullable"}, nil, nil
+		}
+
+		return []string{"name", "column_name", "data_type", "udt_name", "is_nullable"}, [][]driver.Value{{"schema_migrations", "version", "bigint", "int8", "NO"}}, nil
+	case strings.HasPrefix(query, "SELECT version, name, applied_at FROM schema_migrations"):
+		rows := [][]driver.Value{}
+		for version, name := range f.applied {
+			rows = append(rows, []driver.Value{version, name, appliedAt})
+		}
+
+		sort.Slice(rows, func(i, j int) bool { return rows[i][0].(int64) < rows[j][0].(int64) })
+
+		return []string{"version", "name", "applied_at"}, rows, nil
+	case strings.HasPrefix(query, "INSERT INTO schema_migrations"):
+		f.applied[args[0].(int64)] = args[1].(string)
+	case strings.HasPrefix(query, "DELETE FROM schema_migrations"):
+		delete(f.applied, args[0].(int64))
+	}
+
+	return nil, nil, nil
+}
+
+func Test_Load_OrdersMigrations(t *testing.T) {
+	migrations, err := migrate.Load(exampleMigrations)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []migrate.Migration{
+		{Version: 1, Name: "create_users", Up: "CREATE TABLE users (id bigint)", Down: "DROP TABLE users"},
+		{Version: 2, Name: "add_email", Up: "ALTER TABLE users ADD COLUMN email text", Down: "ALTER TABLE users DROP COLUMN email"},
+		{Version: 10, Name: "create_orders", Up: "CREATE TABLE orders (id bigint)"},
+	}, migrations)
+
+	_, err = migrate.Load(fstest.MapFS{
+		"0001_a.up.sql": {Data: []byte("SELECT 1")},
+		"0001_b.up.sql": {Data: []byte("SELECT 1")},
+	})
+	assert.EqualError(t, err, "migrate: version 1 is used by a and b")
+
+	_, err = migrate.Load(fstest.MapFS{"0001_a.down.sql": {Data: []byte("SELECT 1")}})
+	assert.EqualError(t, err, "migrate: version 1 a has no up migration")
+}
+
+func Test_Up_AppliesPendingMigrationsInTransactions(t *testing.T) {
+	tracking := &fakeTracking{applied: map[int64]string{1: "create_users"}}
+	db, fake := fakedb.New(tracking.handle)
+
+	m, err := migrate.New(db, sqb.Psql(), exampleMigrations)
+	assert.NoError(t, err)
+
+	applied, err := m.Up(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{2, 10}, versions(applied))
+	assert.Equal(t, map[int64]string{1: "create_users", 2: "add_email", 10: "create_orders"}, tracking.applied)
+
+	log := fake.Executed()
+	assert.Equal(t, []string{
+		"SELECT pg_advisory_lock($1)",
+		"CREATE TABLE IF NOT EXISTS schema_migrations (version bigint NOT NULL, name text NOT NULL, applied_at timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP, PRIMARY KEY (version))",
+		"SELECT version, name, applied_at FROM schema_migrations",
+		"BEGIN",
+		"ALTER TABLE users ADD COLUMN email text",
+		"INSERT INTO schema_migrations (version, name) VALUES ($1, $2)",
+		"COMMIT",
+		"BEGIN",
+		"CREATE TABLE orders (id bigint)",
+		"INSERT INTO schema_migrations (version, name) VALUES ($1, $2)",
+		"COMMIT",
+		"SELECT pg_advisory_unlock($1)",
+	}, log)
+
+	applied, err = m.Up(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, applied)
+}
+
+func Test_Up_RollsBackFailedMigration(t *testing.T) {
+	tracking := &fakeTracking{applied: map[int64]string{}, failOn: "CREATE TABLE orders"}
+	db, fake := fakedb.New(tracking.handle)
+
+	m, err := migrate.New(db, sqb.Psql(), exampleMigrations)
+	assert.NoError(t, err)
+
+	applied, err := m.Up(context.Background())
+	assert.EqualError(t, err, "migrate: up 10 create_orders: syntax error")
+	assert.Equal(t, []int64{1, 2}, versions(applied))
+	assert.Equal(t, map[int64]string{1: "create_users", 2: "add_email"}, tracking.applied)
+
+	log := fake.Executed()
+	assert.Equal(t, []string{"BEGIN", "CREATE TABLE orders (id bigint)", "ROLLBACK", "SELECT pg_advisory_unlock($1)"}, log[len(log)-4:])
+}
+
+func Test_Down_RollsBackLatestMigrations(t *testing.T) {
+	tracking := &fakeTracking{applied: map[int64]string{1: "create_users", 2: "add_email"}}
+	db, fake := fakedb.New(tracking.handle)
+
+	m, err := migrate.New(db, sqb.InlineLimit(sqb.Psql()), exampleMigrations, migrate.WithLockKey(42))
+	assert.NoError(t, err)
+
+	rolledBack, err := m.Down(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{2}, versions(rolledBack))
+	assert.Equal(t, map[int64]string{1: "create_users"}, tracking.applied)
+	assert.Contains(t, fake.Executed(), "DELETE FROM schema_migrations WHERE version = $1")
+
+	tracking.applied[10] = "create_orders"
+	_, err = m.Down(context.Background(), 1)
+	assert.EqualError(t, err, "migrate: down 10 create_orders: no down migration")
+
+	executed := len(fake.Executed())
+	_, err = m.Down(context.Background(), -1)
+	assert.EqualError(t, err, "migrate: down needs at least 1 step, got -1")
+	assert.Len(t, fake.Executed(), executed)
+}
+
+func Test_Status_ReportsMigrations(t *testing.T) {
+	tracking := &fakeTracking{applied: map[int64]string{1: "create_users", 7: "removed"}}
+	db, fake := fakedb.New(tracking.handle)
+
+	m, err := migrate.New(db, sqb.Psql(), exampleMigrations)
+	assert.NoError(t, err)
+
+	statuses, err := m.Status(context.Background())
+	assert.NoError(t, err)
+
+	summary := []string{}
+	for _, status := range statuses {
+		summary = append(summary, strings.Join([]string{status.Name, map[bool]string{true: "applied", false: "pending"}[status.Applied]}, " "))
+	}
+
+	assert.Equal(t, []string{"create_users applied", "add_email pending", "removed applied", "create_orders pending"}, summary)
+	assert.Equal(t, appliedAt, statuses[0].AppliedAt)
+
+	for _, query := range fake.Executed() {
+		assert.True(t, strings.HasPrefix(query, "SELECT"), query)
+	}
+}
+
+func Test_Status_DoesNotCreateTheTrackingTable(t *testing.T) {
+	tracking := &fakeTracking{applied: map[int64]string{}, missing: true}
+	db, fake := fakedb.New(tracking.handle)
+
+	m, err := migrate.New(db, sqb.Psql(), exampleMigrations)
+	assert.NoError(t, err)
+
+	statuses, err := m.Status(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, statuses, 3)
+
+	for _, status := range statuses {
+		assert.False(t, status.Applied)
+	}
+
+	assert.Len(t, fake.Executed(), 1)
+	assert.True(t, strings.HasPrefix(fake.Executed()[0], "SELECT t.name"))
+}
+
+func versions(migrations []migrate.Migration) []int64 {
+	v := []int64{}
+	for _, migration := range migrations {
+		v = append(v, migration.Version)
+	}
+
+	return v
+}
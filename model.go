@@ -0,0 +1,129 @@
+package sqb
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+/*
+	Table models are structs describing the columns of a table. Each field is mapped to a column by its
+	struct tag, see tags.go. Untagged fields are skipped, unless the table is given a NamingStrategy to name
+	them.
+
+	Embedded structs, such as shared timestamps, are flattened into the model's columns. Nested structs
+	tagged inline are flattened too, with their column names prefixed by the tag's name, e.g.
+	Address Address `psql:"addr_,inline"` produces addr_street, addr_city and so on.
+*/
+
+// Names the column for an untagged model field
+type NamingStrategy func(fieldName string) string
+
+// Names columns in snake case, e.g. CreatedAt becomes created_at and UserID becomes user_id
+func SnakeCase(fieldName string) string {
+	runes := []rune(fieldName)
+	b := strings.Builder{}
+
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			// Start a new word after a lower case letter or digit, or at the last capital of an acronym
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+				b.WriteRune('_')
+			}
+
+			r = unicode.ToLower(r)
+		}
+
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+type TableOption func(o *tableOptions)
+
+type tableOptions struct {
+	naming NamingStrategy
+}
+
+// Name untagged model fields with naming, rather than skipping them
+func WithNamingStrategy(naming NamingStrategy) TableOption {
+	return func(o *tableOptions) {
+		o.naming = naming
+	}
+}
+
+// Reflect over a model struct, returning its columns in field order
+func modelColumns(modelType reflect.Type, dialect Dialect, options tableOptions) []*Column {
+	columns := appendModelColumns(nil, modelType, nil, "", dialect, options)
+	names := map[string]*Column{}
+
+	for _, column := range columns {
+		if other, ok := names[column.name]; ok {
+			panic(fmt.Sprintf("QueryBuilder: fields %s and %s are both named %s", fieldPath(modelType, other.index), fieldPath(modelType, column.index), column.name))
+		}
+
+		names[column.name] = column
+	}
+
+	return columns
+}
+
+// Embedded structs are flattened into the columns of the struct embedding them, as are structs tagged
+// inline, whose column names are prefixed with the tag's name. Structs which can be scanned, such as
+// time.Time, are columns themselves.
+func appendModelColumns(columns []*Column, modelType reflect.Type, index []int, prefix string, dialect Dialect, options tableOptions) []*Column {
+	for i := 0; i < modelType.NumField(); i++ {
+		field := modelType.Field(i)
+		fieldIndex := append(append([]int{}, index...), i)
+
+		tag, tagged := field.Tag.Lookup(dialect.StructTag())
+		if tag == "-" {
+			continue
+		}
+
+		name, tagOptions := parseTag(tag)
+		_, inline := tagOptions["inline"]
+
+		flatten := field.Type.Kind() == reflect.Struct && !canScan(field.Type) && !isJSONType(field.Type)
+		if flatten && (inline || (field.Anonymous && name == "")) {
+			columns = appendModelColumns(columns, field.Type, fieldIndex, prefix+name, dialect, options)
+			continue
+		}
+
+		if !tagged && (options.naming == nil || !field.IsExported()) {
+			continue
+		}
+
+		if name == "" {
+			if options.naming == nil {
+				panic(fmt.Sprintf("QueryBuilder: field %s has no column name", field.Name))
+			}
+
+			name = options.naming(field.Name)
+		}
+
+		column := NewColumn(field.Type)
+		column.name = prefix + name
+		column.index = fieldIndex
+		column.applyTagOptions(tagOptions)
+
+		columns = append(columns, column)
+	}
+
+	return columns
+}
+
+// Describe the field at index for error messages, e.g. Timestamps.CreatedAt
+func fieldPath(modelType reflect.Type, index []int) string {
+	names := make([]string, 0, len(index))
+
+	for _, i := range index {
+		field := modelType.Field(i)
+		names = append(names, field.Name)
+		modelType = field.Type
+	}
+
+	return strings.Join(names, ".")
+}
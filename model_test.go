@@ -0,0 +1,143 @@
+package sqb_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	sqb "github.com/themanciraptor/SQb"
+	"github.com/themanciraptor/SQb/internal/fakedb"
+)
+
+type exampleUserModel struct {
+	ID        int64     `psql:"id,pk,default"`
+	Email     string    `psql:"email"`
+	Nickname  string    `psql:"nickname,omitempty"`
+	CreatedAt time.Time `psql:"created_at,readonly,default=now()"`
+	Secret    string    `psql:"-"`
+	LastSeen  time.Time
+	loginHash string
+}
+
+func Test_SnakeCase(t *testing.T) {
+	testCases := map[string]string{
+		"Name":       "name",
+		"CreatedAt":  "created_at",
+		"UserID":     "user_id",
+		"HTTPServer": "http_server",
+		"ID":         "id",
+		"A1B2":       "a1_b2",
+	}
+
+	for fieldName, expected := range testCases {
+		t.Run(fieldName, func(t *testing.T) {
+			assert.Equal(t, expected, sqb.SnakeCase(fieldName))
+		})
+	}
+}
+
+func Test_NewTable_ReadsTagOptions(t *testing.T) {
+	tt := sqb.NewTable[any]("users", sqb.Psql(), &exampleUserModel{})
+
+	assert.Equal(t, []string{"id"}, tt.PrimaryKey())
+	assert.NotPanics(t, func() { tt.AssertColumnExists("created_at") })
+
+	for _, columnName := range []string{"", "id,pk,default", "Secret", "secret", "last_seen", "login_hash"} {
+		assert.Panics(t, func() { tt.AssertColumnExists(columnName) }, columnName)
+	}
+}
+
+func Test_NewTable_NamesUntaggedFieldsWithNamingStrategy(t *testing.T) {
+	tt := sqb.NewTable[any]("users", sqb.Psql(), &exampleUserModel{}, sqb.WithNamingStrategy(sqb.SnakeCase))
+
+	assert.NotPanics(t, func() { tt.AssertColumnExists("last_seen") })
+	assert.Panics(t, func() { tt.AssertColumnExists("secret") })
+	assert.Panics(t, func() { tt.AssertColumnExists("login_hash") })
+}
+
+func Test_NewTable_PanicsOnDuplicateColumnNames(t *testing.T) {
+	type duplicateModel struct {
+		Name     string `psql:"name"`
+		Nickname string `psql:"name"`
+	}
+
+	assert.PanicsWithValue(t, "QueryBuilder: fields Name and Nickname are both named name", func() {
+		sqb.NewTable[any]("users", sqb.Psql(), &duplicateModel{})
+	})
+}
+
+func Test_GetByID_FiltersByPrimaryKey(t *testing.T) {
+	params := sqb.NewParamList(sqb.Psql())
+	tt := sqb.NewTable[any]("users", sqb.Psql(), &exampleUserModel{}).GetByID(int64(4))
+
+	assert.Equal(t, "id = $1", tt.BuildFilter(params))
+	assert.Equal(t, []interface{}{int64(4)}, params.GetParamList())
+
+	assert.PanicsWithValue(t, "GetByID: table exampleTable has no primary key", func() {
+		sqb.NewTable[any]("exampleTable", sqb.Psql(), &exampleModel{}).GetByID("doom")
+	})
+
+	assert.PanicsWithValue(t, "GetByID: table users has primary key [id], got 2 values", func() {
+		sqb.NewTable[any]("users", sqb.Psql(), &exampleUserModel{}).GetByID(int64(4), int64(5))
+	})
+}
+
+type exampleTimestamps struct {
+	CreatedAt time.Time `psql:"created_at,readonly,default=now()"`
+	UpdatedAt time.Time `psql:"updated_at"`
+}
+
+type exampleAddress struct {
+	Street string `psql:"street"`
+	City   string `psql:"city"`
+}
+
+type exampleCustomerModel struct {
+	ID int64 `psql:"id,pk,default"`
+	exampleTimestamps
+	Address exampleAddress `psql:"addr_,inline"`
+}
+
+func Test_NewTable_FlattensEmbeddedAndInlineStructs(t *testing.T) {
+	tt := sqb.NewTable[any]("customers", sqb.Psql(), &exampleCustomerModel{})
+
+	for _, columnName := range []string{"id", "created_at", "updated_at", "addr_street", "addr_city"} {
+		assert.NotPanics(t, func() { tt.AssertColumnExists(columnName) }, columnName)
+	}
+
+	assert.Panics(t, func() { tt.AssertColumnExists("street") })
+	assert.Panics(t, func() { tt.AssertColumnExists("addr_") })
+
+	type duplicateModel struct {
+		exampleTimestamps
+		Created time.Time `psql:"created_at"`
+	}
+
+	assert.PanicsWithValue(t, "QueryBuilder: fields exampleTimestamps.CreatedAt and Created are both named created_at", func() {
+		sqb.NewTable[any]("customers", sqb.Psql(), &duplicateModel{})
+	})
+}
+
+func Test_NewAutoAccumulator_ScansNestedFields(t *testing.T) {
+	updated := time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)
+	db, _ := fakedb.New(func(query string, args []driver.Value) ([]string, [][]driver.Value, error) {
+		return []string{"id", "updated_at", "addr_city"}, [][]driver.Value{{int64(1), updated, "Paris"}, {int64(2), updated, "Oslo"}}, nil
+	})
+
+	acc := sqb.NewAutoAccumulator[exampleCustomerModel](sqb.Psql())
+	q := sqb.NewTable[exampleCustomerModel]("customers", sqb.Psql(), &exampleCustomerModel{}).
+		Select("id", "updated_at", "addr_city").
+		LoadReceiversFromAccumulator(acc).
+		Build(acc, sqb.Psql())
+
+	assert.Equal(t, "SELECT id, updated_at, addr_city FROM customers", q.GetQuery())
+	assert.NoError(t, q.Run(context.Background(), sqb.NewPreparedRunner(db, 1)))
+
+	results := acc.GetResults()
+	assert.Equal(t, int64(1), results[0].ID)
+	assert.Equal(t, updated, results[0].UpdatedAt)
+	assert.Equal(t, "Paris", results[0].Address.City)
+	assert.Equal(t, "Oslo", results[1].Address.City)
+}
@@ -1,8 +1,17 @@
 package sqb
 
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
 type ParamList struct {
 	params  []interface{}
 	dialect Dialect
+
+	// Problems found while building clauses, e.g. features the dialect does not support
+	errs []error
 }
 
 func NewParamList(dialect Dialect) *ParamList {
@@ -12,17 +21,57 @@ func NewParamList(dialect Dialect) *ParamList {
 	}
 }
 
+// Records v as a param, reusing the param of an equal value recorded earlier. Use this only where a value is
+// deliberately shared, filters each record their own param.
 func (p *ParamList) RecordValueAndReturnParam(v interface{}) string {
+	v = bindValue(v)
+
+	// Values such as slices and maps cannot be compared, so are always recorded as a new param. Structs
+	// wrapping them, e.g. pq.GenericArray, have comparable types but panic when compared.
+	if v != nil && !reflect.ValueOf(v).Comparable() {
+		return p.AppendValueAndReturnParam(v)
+	}
+
 	for k := range p.params {
 		if p.params[k] == v {
-			return p.dialect.FormatParam(k)
+			return p.dialect.FormatParam(k + 1)
 		}
 	}
 
-	p.params = append(p.params, v)
+	return p.AppendValueAndReturnParam(v)
+}
+
+// Records v as a new param even if an equal value has already been recorded. Use this where the shape of
+// the query must not depend on the values bound to it.
+func (p *ParamList) AppendValueAndReturnParam(v interface{}) string {
+	p.params = append(p.params, bindValue(v))
 	return p.dialect.FormatParam(len(p.params))
 }
 
 func (p *ParamList) GetParamList() []interface{} {
 	return p.params
 }
+
+func (p *ParamList) Dialect() Dialect {
+	return p.dialect
+}
+
+// Record a problem which prevents the query from being built. Clauses should still return their best
+// attempt at building, the query is discarded once building finishes.
+func (p *ParamList) RecordError(err error) {
+	p.errs = append(p.errs, err)
+}
+
+// Record an error for each named param. Drivers cannot bind them, only a Template can.
+func (p *ParamList) recordUnboundParams() {
+	for _, param := range p.params {
+		if np, ok := param.(*NamedParam); ok {
+			p.RecordError(fmt.Errorf("param %s must be bound through a Template, use Compile", np.name))
+		}
+	}
+}
+
+// The problems recorded while building, joined into a single error
+func (p *ParamList) Err() error {
+	return errors.Join(p.errs...)
+}
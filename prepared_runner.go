@@ -0,0 +1,171 @@
+package sqb
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+/*
+	A PreparedRunner runs queries through prepared statements. Tables build deterministic SQL, so each query
+	shape maps to a single statement which can be prepared once and reused for any params. Statements are held
+	in a bounded LRU cache keyed by the built SQL.
+*/
+
+type PreparedRunner struct {
+	db       *sql.DB
+	capacity int
+
+	mu sync.Mutex
+	// Map built SQL to its element in order
+	statements map[string]*list.Element
+	// Most recently used statements are at the front
+	order *list.List
+
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+// A cached statement is only closed once it has been evicted and no running queries still hold it.
+type preparedStatement struct {
+	query   string
+	stmt    *sql.Stmt
+	refs    int
+	evicted bool
+}
+
+// capacity is the maximum number of statements kept prepared at once, and must be positive.
+func NewPreparedRunner(db *sql.DB, capacity int) *PreparedRunner {
+	if capacity < 1 {
+		panic("PreparedRunner: capacity must be positive")
+	}
+
+	return &PreparedRunner{
+		db:         db,
+		capacity:   capacity,
+		statements: map[string]*list.Element{},
+		order:      list.New(),
+	}
+}
+
+func (p *PreparedRunner) RunQuery(ctx context.Context, query string, params []interface{}) (tempRows, func(ctx context.Context), error) {
+	ps, err := p.acquire(ctx, query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, err := ps.stmt.QueryContext(ctx, params...)
+	if err != nil {
+		p.release(ps)
+		return nil, nil, err
+	}
+
+	return rows, func(ctx context.Context) {
+		rows.Close()
+		p.release(ps)
+	}, nil
+}
+
+// Run a one-off query without preparing or caching a statement
+func (p *PreparedRunner) runUnprepared(ctx context.Context, query string, params []interface{}) (tempRows, func(ctx context.Context), error) {
+	rows, err := p.db.QueryContext(ctx, query, params...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return rows, func(ctx context.Context) {
+		rows.Close()
+	}, nil
+}
+
+// The number of queries which reused a cached statement
+func (p *PreparedRunner) Hits() int64 {
+	return p.hits.Load()
+}
+
+// The number of queries which had to prepare a new statement
+func (p *PreparedRunner) Misses() int64 {
+	return p.misses.Load()
+}
+
+// Close evicts every cached statement. Statements still held by running queries are closed once released.
+func (p *PreparedRunner) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var errs []error
+	for p.order.Len() > 0 {
+		errs = append(errs, p.evict(p.order.Back()))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (p *PreparedRunner) acquire(ctx context.Context, query string) (*preparedStatement, error) {
+	p.mu.Lock()
+	if e, ok := p.statements[query]; ok {
+		ps := e.Value.(*preparedStatement)
+		ps.refs++
+		p.order.MoveToFront(e)
+		p.mu.Unlock()
+
+		p.hits.Add(1)
+		return ps, nil
+	}
+	p.mu.Unlock()
+
+	// Prepare outside of the lock so that a slow prepare doesn't block queries using other statements.
+	p.misses.Add(1)
+	stmt, err := p.db.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, errors.Join(err, errors.New("failed to prepare query"))
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Another query may have prepared the same statement in the meantime, prefer the cached one.
+	if e, ok := p.statements[query]; ok {
+		stmt.Close()
+
+		ps := e.Value.(*preparedStatement)
+		ps.refs++
+		p.order.MoveToFront(e)
+		return ps, nil
+	}
+
+	ps := &preparedStatement{query: query, stmt: stmt, refs: 1}
+	p.statements[query] = p.order.PushFront(ps)
+
+	for p.order.Len() > p.capacity {
+		p.evict(p.order.Back())
+	}
+
+	return ps, nil
+}
+
+func (p *PreparedRunner) release(ps *preparedStatement) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ps.refs--
+	if ps.evicted && ps.refs == 0 {
+		ps.stmt.Close()
+	}
+}
+
+// Must be called while holding mu
+func (p *PreparedRunner) evict(e *list.Element) error {
+	ps := p.order.Remove(e).(*preparedStatement)
+	delete(p.statements, ps.query)
+	ps.evicted = true
+
+	if ps.refs == 0 {
+		return ps.stmt.Close()
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package sqb_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	sqb "github.com/themanciraptor/SQb"
+	"github.com/themanciraptor/SQb/internal/fakedb"
+)
+
+func nameHandler(query string, args []driver.Value) ([]string, [][]driver.Value, error) {
+	return []string{"cool"}, [][]driver.Value{{"doom"}, {"gloom"}}, nil
+}
+
+func buildNameQuery(name string) *sqb.Query[exampleResult] {
+	acc := sqb.NewAccumulator(func(r *exampleResult) map[string]interface{} {
+		return map[string]interface{}{
+			"cool": &r.Name,
+		}
+	})
+
+	return sqb.NewTable[exampleResult]("exampleTable", sqb.Psql(), &exampleModel{}).
+		LoadReceiversFromAccumulator(acc).
+		ColumnEquals("cool", name).
+		Build(acc, sqb.Psql())
+}
+
+func Test_PreparedRunner_ReusesStatementsForTheSameQuery(t *testing.T) {
+	db, fake := fakedb.New(nameHandler)
+	runner := sqb.NewPreparedRunner(db, 4)
+
+	for _, name := range []string{"doom", "gloom", "doom"} {
+		q := buildNameQuery(name)
+		assert.NoError(t, q.Run(context.Background(), runner))
+	}
+
+	assert.Equal(t, int64(1), runner.Misses())
+	assert.Equal(t, int64(2), runner.Hits())
+	assert.Equal(t, int64(1), fake.Prepares())
+}
+
+func Test_PreparedRunner_ScansResults(t *testing.T) {
+	db, _ := fakedb.New(nameHandler)
+	runner := sqb.NewPreparedRunner(db, 4)
+
+	acc := sqb.NewAccumulator(func(r *exampleResult) map[string]interface{} {
+		return map[string]interface{}{
+			"cool": &r.Name,
+		}
+	})
+
+	q := sqb.NewTable[exampleResult]("exampleTable", sqb.Psql(), &exampleModel{}).
+		LoadReceiversFromAccumulator(acc).
+		Build(acc, sqb.Psql())
+
+	assert.NoError(t, q.Run(context.Background(), runner))
+	assert.Equal(t, []exampleResult{{Name: "doom"}, {Name: "gloom"}}, acc.GetResults())
+}
+
+func Test_PreparedRunner_EvictsLeastRecentlyUsed(t *testing.T) {
+	db, fake := fakedb.New(nameHandler)
+	runner := sqb.NewPreparedRunner(db, 1)
+	ctx := context.Background()
+
+	first := sqb.NewTable[exampleResult]("exampleTable", sqb.Psql(), &exampleModel{}).
+		SetColumnReceiver("cool", new(string)).
+		Build(sqb.NewAccumulator(func(r *exampleResult) map[string]interface{} { return nil }), sqb.Psql())
+	second := buildNameQuery("doom")
+
+	assert.NoError(t, first.Run(ctx, runner))
+	assert.NoError(t, second.Run(ctx, runner))
+	assert.NoError(t, first.Run(ctx, runner))
+
+	assert.Equal(t, int64(3), runner.Misses())
+	assert.Equal(t, int64(0), runner.Hits())
+	assert.Equal(t, int64(3), fake.Prepares())
+	assert.Equal(t, int64(2), fake.Closes())
+
+	assert.NoError(t, runner.Close())
+	assert.Equal(t, int64(3), fake.Closes())
+}
+
+func Test_PreparedRunner_IsSafeForConcurrentUse(t *testing.T) {
+	db, _ := fakedb.New(nameHandler)
+	runner := sqb.NewPreparedRunner(db, 2)
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			names := []string{"doom", "gloom", "bloom"}
+			q := buildNameQuery(names[i%len(names)])
+			if i%2 == 0 {
+				q = sqb.NewTable[exampleResult]("otherTable", sqb.Psql(), &exampleModel{}).
+					SetColumnReceiver("cool", new(string)).
+					Build(sqb.NewAccumulator(func(r *exampleResult) map[string]interface{} { return nil }), sqb.Psql())
+			}
+
+			assert.NoError(t, q.Run(context.Background(), runner))
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, int64(50), runner.Hits()+runner.Misses())
+}
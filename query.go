@@ -10,6 +10,13 @@ type Query[T any] struct {
 	query    string
 	params   []interface{}
 
+	// The dialect the query was built for, used to render DebugString
+	dialect Dialect
+
+	// Describe the query to hooks, e.g. exampleTable and SELECT
+	table     string
+	statement string
+
 	accumulator Accumulator[T]
 }
 
@@ -36,19 +43,29 @@ type tempDriver interface {
 }
 
 func (q *Query[T]) Run(ctx context.Context, psql tempDriver) error {
+	ctx = withQueryInfo(ctx, queryInfo{table: q.table, statement: q.statement, dialect: q.dialect})
+
 	res, closer, err := psql.RunQuery(ctx, q.query, q.params)
 	if err != nil {
 		return errors.Join(err, errors.New("failed to run query"))
 	}
 	defer closer(ctx)
 
+	resettable, _ := q.accumulator.(ResettableAccumulator[T])
+
 	for res.Next() {
+		if resettable != nil {
+			resettable.Reset()
+		}
+
 		err := res.Scan(q.scanList...)
 		if err != nil {
 			return errors.Join(err, errors.New("failed to scan row"))
 		}
 
-		q.accumulator.Acc()
+		if q.accumulator != nil {
+			q.accumulator.Acc()
+		}
 	}
 	return nil
 }
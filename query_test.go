@@ -0,0 +1,112 @@
+package sqb_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+	"testing/quick"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	sqb "github.com/themanciraptor/SQb"
+	"github.com/themanciraptor/SQb/internal/fakedb"
+)
+
+// Generated rows, where nil fields are scanned as NULL
+type exampleRow struct {
+	Name  *string
+	Stars *int64
+	Loves []string
+}
+
+func (r exampleRow) values() []driver.Value {
+	values := []driver.Value{nil, nil, nil}
+
+	if r.Name != nil {
+		values[0] = *r.Name
+	}
+
+	if r.Loves != nil {
+		values[1], _ = pq.StringArray(r.Loves).Value()
+	}
+
+	if r.Stars != nil {
+		values[2] = *r.Stars
+	}
+
+	return values
+}
+
+func (r exampleRow) expected() exampleResult {
+	e := exampleResult{Loves: r.Loves}
+
+	if r.Name != nil {
+		e.Name = *r.Name
+	}
+
+	if r.Stars != nil {
+		e.NumStars = *r.Stars
+	}
+
+	return e
+}
+
+func Test_Run_IsolatesRows(t *testing.T) {
+	property := func(rows []exampleRow) bool {
+		db, _ := fakedb.New(func(query string, args []driver.Value) ([]string, [][]driver.Value, error) {
+			values := make([][]driver.Value, 0, len(rows))
+			for _, r := range rows {
+				values = append(values, r.values())
+			}
+
+			return []string{"cool", "loves", "number_of_star"}, values, nil
+		})
+
+		acc := sqb.NewAccumulator(func(r *exampleResult) map[string]interface{} {
+			return map[string]interface{}{
+				"cool":           sqb.NewNull(&r.Name),
+				"loves":          &r.Loves,
+				"number_of_star": sqb.NewNull(&r.NumStars),
+			}
+		})
+
+		q := sqb.NewTable[exampleResult]("exampleTable", sqb.Psql(), &exampleModel{}).
+			LoadReceiversFromAccumulator(acc).
+			Build(acc, sqb.Psql())
+
+		if err := q.Run(context.Background(), sqb.NewPreparedRunner(db, 1)); err != nil {
+			t.Log(err)
+			return false
+		}
+
+		expected := make([]exampleResult, 0, len(rows))
+		for _, r := range rows {
+			expected = append(expected, r.expected())
+		}
+
+		return assert.Equal(t, expected, append([]exampleResult{}, acc.GetResults()...))
+	}
+
+	assert.NoError(t, quick.Check(property, nil))
+}
+
+func Test_Run_ResultsDoNotShareSlices(t *testing.T) {
+	db, _ := fakedb.New(func(query string, args []driver.Value) ([]string, [][]driver.Value, error) {
+		return []string{"loves"}, [][]driver.Value{{"{doom}"}, {"{}"}, {"{gloom}"}}, nil
+	})
+
+	acc := NewResultAccumulator()
+	q := sqb.NewTable[exampleResult]("exampleTable", sqb.Psql(), &exampleModel{}).
+		SetColumnReceiver("loves", acc.GetColumnReceiverMap()["loves"]).
+		Build(acc, sqb.Psql())
+
+	assert.NoError(t, q.Run(context.Background(), sqb.NewPreparedRunner(db, 1)))
+
+	results := acc.GetResults()
+	_ = append(results[1].Loves, "changed")
+	results[2].Loves[0] = "changed"
+
+	assert.Equal(t, []string{"doom"}, results[0].Loves)
+	assert.Equal(t, []string{}, results[1].Loves)
+	assert.Equal(t, []string{"changed"}, results[2].Loves)
+}
@@ -0,0 +1,108 @@
+package sqb
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+/*
+	A Schema registers each table model once, under its table name, and caches the columns reflected from it.
+	Tables for a registered model are then made with From, which copies the cached columns instead of
+	reflecting over the model again. A Schema is safe for concurrent use.
+*/
+
+type Schema struct {
+	dialect Dialect
+	options tableOptions
+
+	mu     sync.RWMutex
+	models map[reflect.Type]*schemaModel
+	tables map[string]reflect.Type
+}
+
+type schemaModel struct {
+	tableName string
+	columns   []*Column
+}
+
+func NewSchema(dialect Dialect, options ...TableOption) *Schema {
+	s := &Schema{
+		dialect: dialect,
+		models:  map[reflect.Type]*schemaModel{},
+		tables:  map[string]reflect.Type{},
+	}
+
+	for _, option := range options {
+		option(&s.options)
+	}
+
+	return s
+}
+
+// Register the model M as the table tableName. Panics if either is already registered.
+func Register[M any](s *Schema, tableName string) {
+	modelType := reflect.TypeOf((*M)(nil)).Elem()
+	if modelType.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("Register: model must be a struct type, got %s", modelType))
+	}
+
+	if tableName == "" {
+		panic(fmt.Sprintf("Register: %s needs a table name", modelType))
+	}
+
+	columns := modelColumns(modelType, s.dialect, s.options)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if registered, ok := s.models[modelType]; ok {
+		panic(fmt.Sprintf("Register: %s is already registered as table %s", modelType, registered.tableName))
+	}
+
+	if other, ok := s.tables[tableName]; ok {
+		panic(fmt.Sprintf("Register: table %s is already registered for %s", tableName, other))
+	}
+
+	s.models[modelType] = &schemaModel{tableName: tableName, columns: columns}
+	s.tables[tableName] = modelType
+}
+
+// Make a table for the registered model M, whose rows are scanned into M
+func From[M any](s *Schema) *Table[M] {
+	return FromAs[M, M](s)
+}
+
+// Make a table for the registered model M, whose rows are scanned into T
+func FromAs[T any, M any](s *Schema) *Table[T] {
+	modelType := reflect.TypeOf((*M)(nil)).Elem()
+
+	s.mu.RLock()
+	model, ok := s.models[modelType]
+	s.mu.RUnlock()
+
+	if !ok {
+		panic(fmt.Sprintf("From: %s is not registered", modelType))
+	}
+
+	// Tables keep receivers on their columns, so each gets its own copy
+	columns := make([]*Column, 0, len(model.columns))
+	for _, column := range model.columns {
+		columns = append(columns, column.clone())
+	}
+
+	return newTable[T](model.tableName, s.dialect, modelType, columns)
+}
+
+// The table name registered for the model M
+func TableNameOf[M any](s *Schema) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	model, ok := s.models[reflect.TypeOf((*M)(nil)).Elem()]
+	if !ok {
+		return "", false
+	}
+
+	return model.tableName, true
+}
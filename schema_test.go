@@ -0,0 +1,92 @@
+package sqb_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	sqb "github.com/themanciraptor/SQb"
+	"github.com/themanciraptor/SQb/internal/fakedb"
+)
+
+func Test_Schema_BuildsRegisteredTables(t *testing.T) {
+	schema := sqb.NewSchema(sqb.Psql())
+	sqb.Register[exampleUserModel](schema, "users")
+
+	tableName, ok := sqb.TableNameOf[exampleUserModel](schema)
+	assert.True(t, ok)
+	assert.Equal(t, "users", tableName)
+
+	acc := sqb.NewAutoAccumulator[exampleUserModel](sqb.Psql())
+	q := sqb.From[exampleUserModel](schema).GetByID(int64(4)).LoadReceiversFromAccumulator(acc).Build(acc, sqb.Psql())
+
+	assert.Equal(t, "SELECT created_at, email, id, nickname FROM users WHERE id = $1", q.GetQuery())
+	assert.Equal(t, "INSERT INTO users (email) VALUES ($1)", sqb.FromAs[any, exampleUserModel](schema).Insert(exampleUserModel{Email: "a@b.c"}).Build(sqb.Psql()).GetQuery())
+}
+
+func Test_Schema_TablesDoNotShareReceivers(t *testing.T) {
+	schema := sqb.NewSchema(sqb.Psql())
+	sqb.Register[exampleModel](schema, "exampleTable")
+
+	newAccumulator := func() sqb.Accumulator[exampleModel] {
+		return sqb.NewAccumulator(func(r *exampleModel) map[string]interface{} {
+			return map[string]interface{}{"cool": &r.Name}
+		})
+	}
+
+	first, second := newAccumulator(), newAccumulator()
+	firstQuery := sqb.From[exampleModel](schema).Select("cool").LoadReceiversFromAccumulator(first).Build(first, sqb.Psql())
+	secondQuery := sqb.From[exampleModel](schema).Select("cool").LoadReceiversFromAccumulator(second).Build(second, sqb.Psql())
+
+	firstReceiver, secondReceiver := firstQuery.GetScanList()[0], secondQuery.GetScanList()[0]
+	assert.NotSame(t, firstReceiver, secondReceiver)
+
+	db, _ := fakedb.New(func(query string, args []driver.Value) ([]string, [][]driver.Value, error) {
+		return []string{"cool"}, [][]driver.Value{{"doom"}}, nil
+	})
+
+	assert.NoError(t, firstQuery.Run(context.Background(), sqb.NewPreparedRunner(db, 1)))
+	assert.Equal(t, []exampleModel{{Name: "doom"}}, first.GetResults())
+	assert.Empty(t, second.GetResults())
+	assert.Equal(t, "", *secondReceiver.(*string))
+
+	// Tables built concurrently must not race on receivers
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			acc := NewResultAccumulator()
+			sqb.FromAs[exampleResult, exampleModel](schema).LoadReceiversFromAccumulator(acc).Build(acc, sqb.Psql())
+		}()
+	}
+
+	wg.Wait()
+}
+
+func Test_Schema_Errors(t *testing.T) {
+	schema := sqb.NewSchema(sqb.Psql())
+	sqb.Register[exampleUserModel](schema, "users")
+
+	assert.PanicsWithValue(t, "Register: sqb_test.exampleUserModel is already registered as table users", func() {
+		sqb.Register[exampleUserModel](schema, "people")
+	})
+
+	assert.PanicsWithValue(t, "Register: table users is already registered for sqb_test.exampleUserModel", func() {
+		sqb.Register[exampleModel](schema, "users")
+	})
+
+	assert.PanicsWithValue(t, "From: sqb_test.exampleModel is not registered", func() {
+		sqb.From[exampleModel](schema)
+	})
+
+	assert.PanicsWithValue(t, "Register: model must be a struct type, got string", func() {
+		sqb.Register[string](schema, "strings")
+	})
+
+	_, ok := sqb.TableNameOf[exampleModel](schema)
+	assert.False(t, ok)
+}
@@ -0,0 +1,144 @@
+package sqb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+/*
+	Scope columns, such as a tenant id, must be filtered by every select, update and delete built from a
+	table. Columns are declared as scope columns with the scope tag option or RequireScope, and their values
+	are bound with Scope, or with ScopeFrom for values carried by a context.Context. Building a statement
+	without a value for each scope column fails, unless the table is marked as an Admin query.
+
+	Scopes are checked wherever the table's statement is built, including subqueries, CTEs and set
+	operations. Tables joined to another are filtered by their scopes in the join condition, see Join.
+*/
+
+type scopeKey struct{}
+
+// Carry a value for the scope column columnName, to be bound with Table.ScopeFrom
+func WithScope(ctx context.Context, columnName string, v interface{}) context.Context {
+	parent, _ := ctx.Value(scopeKey{}).(map[string]interface{})
+
+	scopes := make(map[string]interface{}, len(parent)+1)
+	for name, value := range parent {
+		scopes[name] = value
+	}
+
+	scopes[columnName] = v
+
+	return context.WithValue(ctx, scopeKey{}, scopes)
+}
+
+// Declare columns as scope columns, for models without the scope tag option
+func (t *Table[T]) RequireScope(columnNames ...string) *Table[T] {
+	for _, columnName := range columnNames {
+		t.GetColumn(columnName).scope = true
+	}
+
+	return t
+}
+
+// The table's scope columns, in model field order
+func (t *Table[T]) ScopeColumns() []string {
+	scopeColumns := []string{}
+
+	for _, columnName := range t.columnOrder {
+		if t.fields[columnName].scope {
+			scopeColumns = append(scopeColumns, columnName)
+		}
+	}
+
+	return scopeColumns
+}
+
+// Filter the scope column columnName by v. v may be a NamedParam, to be bound through a Template.
+func (t *Table[T]) Scope(columnName string, v interface{}) *Table[T] {
+	t.AssertFilterClauseValid(columnName, v)
+
+	if !t.fields[columnName].scope {
+		panic(fmt.Sprintf("Scope: column %s of table %s is not a scope column, use ColumnEquals", columnName, t.tableName))
+	}
+
+	t.checkEnumValue(columnName, v)
+	t.scopes[columnName] = v
+
+	return t
+}
+
+// Scope the table by the values ctx carries for its scope columns, see WithScope. Scope columns without a
+// value in ctx are left unbound, so building fails unless they are scoped otherwise.
+func (t *Table[T]) ScopeFrom(ctx context.Context) *Table[T] {
+	scopes, _ := ctx.Value(scopeKey{}).(map[string]interface{})
+
+	for _, columnName := range t.ScopeColumns() {
+		if v, ok := scopes[columnName]; ok {
+			t.Scope(columnName, v)
+		}
+	}
+
+	return t
+}
+
+// Allow statements to be built without values for the table's scope columns, e.g. for maintenance across
+// every tenant. Scope values which are given are still applied.
+func (t *Table[T]) Admin() *Table[T] {
+	t.admin = true
+
+	return t
+}
+
+// Build the table's filters, scope filters first. Records the errors of the table's filters, and an error if a
+// scope column is unbound and the table is not an Admin query.
+func (t *Table[T]) buildFilters(params *ParamList) string {
+	return t.qualify(t.filters(params).Build(params))
+}
+
+// The table's scope filters and filters, with their columns marked by columnMarker
+func (t *Table[T]) filters(params *ParamList) *CompoundClause {
+	for _, err := range t.errs {
+		params.RecordError(err)
+	}
+
+	where := t.scopeFilters(params)
+	if t.filter.NumClauses() > 0 {
+		where.AddClause(t.filter)
+	}
+
+	return where
+}
+
+// The filters on the table's scope columns. Records an error if a scope column is unbound and the table is not
+// an Admin query.
+func (t *Table[T]) scopeFilters(params *ParamList) *CompoundClause {
+	where := NewCompoundClause("AND")
+	unscoped := []string{}
+
+	for _, columnName := range t.ScopeColumns() {
+		v, ok := t.scopes[columnName]
+		if !ok {
+			unscoped = append(unscoped, columnName)
+			continue
+		}
+
+		where.AddClause(NewPrimitiveFilterClause(t.columnRef(columnName), "=", "%s", v))
+	}
+
+	if len(unscoped) > 0 && !t.admin {
+		params.RecordError(fmt.Errorf("table %s must be scoped by %s, use Scope or ScopeFrom, or Admin for unscoped queries",
+			t.tableName, strings.Join(unscoped, ", ")))
+	}
+
+	return where
+}
+
+func (t *Table[T]) buildWhere(params *ParamList) string {
+	filters := t.buildFilters(params)
+	if filters == "" {
+		return ""
+	}
+
+	return fmt.Sprint(` WHERE `, filters)
+}
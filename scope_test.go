@@ -0,0 +1,200 @@
+package sqb_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	sqb "github.com/themanciraptor/SQb"
+)
+
+type exampleDocumentModel struct {
+	ID       int64  `psql:"id,pk,default"`
+	TenantID int64  `psql:"tenant_id,scope"`
+	Title    string `psql:"title"`
+}
+
+func newDocuments() *sqb.Table[any] {
+	return sqb.NewTable[any]("documents", sqb.Psql(), &exampleDocumentModel{})
+}
+
+func Test_Scope_FiltersStatements(t *testing.T) {
+	type testCase struct {
+		description    string
+		build          func() (*sqb.Query[any], error)
+		expectedQuery  string
+		expectedParams []interface{}
+	}
+
+	testCases := []testCase{
+		{
+			description: "select",
+			build: func() (*sqb.Query[any], error) {
+				return newDocuments().Scope("tenant_id", int64(7)).ColumnEquals("title", "a").LoadReceiversFromAccumulator(&exampleDocumentAccumulator{}).TryBuild(nil, sqb.Psql())
+			},
+			expectedQuery:  "SELECT title FROM documents WHERE (tenant_id = $1 AND title = $2)",
+			expectedParams: []interface{}{int64(7), "a"},
+		},
+		{
+			description: "select with a filter equal to the scope",
+			build: func() (*sqb.Query[any], error) {
+				return newDocuments().Scope("tenant_id", int64(1)).ColumnEquals("id", int64(1)).LoadReceiversFromAccumulator(&exampleDocumentAccumulator{}).TryBuild(nil, sqb.Psql())
+			},
+			expectedQuery:  "SELECT title FROM documents WHERE (tenant_id = $1 AND id = $2)",
+			expectedParams: []interface{}{int64(1), int64(1)},
+		},
+		{
+			description: "update",
+			build: func() (*sqb.Query[any], error) {
+				return newDocuments().Scope("tenant_id", int64(7)).GetByID(int64(3)).Update(exampleDocumentModel{Title: "b"}).TryBuild(sqb.Psql())
+			},
+			expectedQuery:  "UPDATE documents SET title = $1 WHERE (tenant_id = $2 AND id = $3)",
+			expectedParams: []interface{}{"b", int64(7), int64(3)},
+		},
+		{
+			description: "delete",
+			build: func() (*sqb.Query[any], error) {
+				return newDocuments().Scope("tenant_id", int64(7)).GetByID(int64(3)).Delete().TryBuild(sqb.Psql())
+			},
+			expectedQuery:  "DELETE FROM documents WHERE (tenant_id = $1 AND id = $2)",
+			expectedParams: []interface{}{int64(7), int64(3)},
+		},
+		{
+			description: "scope from context",
+			build: func() (*sqb.Query[any], error) {
+				ctx := sqb.WithScope(context.Background(), "tenant_id", int64(9))
+				return newDocuments().ScopeFrom(ctx).ColumnEquals("title", "a").Delete().TryBuild(sqb.Psql())
+			},
+			expectedQuery:  "DELETE FROM documents WHERE (tenant_id = $1 AND title = $2)",
+			expectedParams: []interface{}{int64(9), "a"},
+		},
+		{
+			description: "admin",
+			build: func() (*sqb.Query[any], error) {
+				return newDocuments().Admin().Delete().TryBuild(sqb.Psql())
+			},
+			expectedQuery:  "DELETE FROM documents",
+			expectedParams: []interface{}{},
+		},
+		{
+			description: "every row of a tenant",
+			build: func() (*sqb.Query[any], error) {
+				return newDocuments().Scope("tenant_id", int64(7)).Admin().Delete().TryBuild(sqb.Psql())
+			},
+			expectedQuery:  "DELETE FROM documents WHERE tenant_id = $1",
+			expectedParams: []interface{}{int64(7)},
+		},
+		{
+			description: "declared with RequireScope",
+			build: func() (*sqb.Query[any], error) {
+				return sqb.NewTable[any]("users", sqb.Psql(), &exampleUserModel{}).RequireScope("email").Scope("email", "a@b.c").GetByID(int64(3)).Delete().TryBuild(sqb.Psql())
+			},
+			expectedQuery:  "DELETE FROM users WHERE (email = $1 AND id = $2)",
+			expectedParams: []interface{}{"a@b.c", int64(3)},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			q, err := tc.build()
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			assert.Equal(t, tc.expectedQuery, q.GetQuery())
+			assert.Equal(t, tc.expectedParams, q.GetParams())
+		})
+	}
+}
+
+func Test_Scope_RequiredToBuild(t *testing.T) {
+	const unscoped = "table documents must be scoped by tenant_id, use Scope or ScopeFrom, or Admin for unscoped queries"
+
+	_, err := newDocuments().LoadReceiversFromAccumulator(&exampleDocumentAccumulator{}).TryBuild(nil, sqb.Psql())
+	assert.EqualError(t, err, "Build: "+unscoped)
+
+	_, err = newDocuments().GetByID(int64(3)).Update(exampleDocumentModel{Title: "b"}).TryBuild(sqb.Psql())
+	assert.EqualError(t, err, "Build: "+unscoped)
+
+	_, err = newDocuments().ScopeFrom(context.Background()).GetByID(int64(3)).Delete().TryBuild(sqb.Psql())
+	assert.EqualError(t, err, "Build: "+unscoped)
+
+	// As must filters built for other statements
+	params := sqb.NewParamList(sqb.Psql())
+	assert.Equal(t, "id = $1", newDocuments().GetByID(int64(3)).BuildFilter(params))
+	assert.EqualError(t, params.Err(), unscoped)
+
+	params = sqb.NewParamList(sqb.Psql())
+	assert.Equal(t, "(tenant_id = $1 AND id = $2)", newDocuments().Scope("tenant_id", int64(7)).GetByID(int64(3)).BuildFilter(params))
+	assert.NoError(t, params.Err())
+
+	// Scoped tables embedded in other queries must be scoped too
+	params = sqb.NewParamList(sqb.Psql())
+	sqb.NewTable[any]("users", sqb.Psql(), &exampleUserModel{}).Select("id").ColumnInSubquery("id", newDocuments().Select("id")).BuildSelect(params)
+	assert.EqualError(t, params.Err(), unscoped)
+
+	// As must joined tables
+	params = sqb.NewParamList(sqb.Psql())
+	sqb.NewTable[any]("users", sqb.Psql(), &exampleUserModel{}).Select("id").Join(newDocuments(), "id", "id").BuildSelect(params)
+	assert.EqualError(t, params.Err(), unscoped)
+}
+
+func Test_Scope_FiltersJoinedTables(t *testing.T) {
+	users := func() *sqb.Table[any] {
+		return sqb.NewTable[any]("users", sqb.Psql(), &exampleUserModel{}).Select("email")
+	}
+
+	params := sqb.NewParamList(sqb.Psql())
+	query := users().Join(newDocuments().Scope("tenant_id", int64(7)), "id", "id").ColumnEquals("nickname", "doom").BuildSelect(params)
+
+	assert.NoError(t, params.Err())
+	assert.Equal(t, "SELECT users.email FROM users JOIN documents ON users.id = documents.id AND documents.tenant_id = $1 WHERE users.nickname = $2", query)
+	assert.Equal(t, []interface{}{int64(7), "doom"}, params.GetParamList())
+
+	params = sqb.NewParamList(sqb.Psql())
+	query = users().Join(newDocuments().Admin(), "id", "id").BuildSelect(params)
+
+	assert.NoError(t, params.Err())
+	assert.Equal(t, "SELECT users.email FROM users JOIN documents ON users.id = documents.id", query)
+}
+
+func Test_Scope_BindsThroughTemplates(t *testing.T) {
+	acc := &exampleDocumentAccumulator{}
+	template := newDocuments().
+		Scope("tenant_id", sqb.Param[int64]("tenant")).
+		LoadReceiversFromAccumulator(acc).
+		Compile(sqb.Psql())
+
+	q, err := template.Bind(map[string]any{"tenant": int64(4)})
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT title FROM documents WHERE tenant_id = $1", q.GetQuery())
+	assert.Equal(t, []interface{}{int64(4)}, q.GetParams())
+}
+
+func Test_Scope_Errors(t *testing.T) {
+	assert.PanicsWithValue(t, "Scope: column title of table documents is not a scope column, use ColumnEquals", func() {
+		newDocuments().Scope("title", "a")
+	})
+
+	assert.PanicsWithValue(t, "Incorrect type for column. Need int64, got string", func() {
+		newDocuments().ScopeFrom(sqb.WithScope(context.Background(), "tenant_id", "7"))
+	})
+
+	assert.PanicsWithValue(t, "Update: column tenant_id is a scope column and cannot be updated", func() {
+		newDocuments().Update(exampleDocumentModel{}, "tenant_id")
+	})
+}
+
+type exampleDocumentAccumulator struct {
+	title string
+}
+
+func (a *exampleDocumentAccumulator) GetColumnReceiverMap() map[string]interface{} {
+	return map[string]interface{}{"title": &a.title}
+}
+
+func (a *exampleDocumentAccumulator) Acc() {}
+
+func (a *exampleDocumentAccumulator) GetResults() []any {
+	return nil
+}
@@ -0,0 +1,195 @@
+package sqb
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+/*
+	A SetQuery combines the selects of two or more tables with UNION, UNION ALL, INTERSECT or EXCEPT. Every
+	part must select the same number of columns, of compatible types, in the same order. The combined rows are
+	named after the columns of the first part, which is what ORDER BY and the accumulator refer to.
+*/
+
+type SetQuery[T any] struct {
+	operator string
+	parts    []*Table[T]
+
+	// Ordering and limit applied to the combined result
+	orderBy []*OrderByClause
+	limit   *LimitClause
+}
+
+func Union[T any](parts ...*Table[T]) *SetQuery[T] {
+	return newSetQuery("UNION", parts)
+}
+
+func UnionAll[T any](parts ...*Table[T]) *SetQuery[T] {
+	return newSetQuery("UNION ALL", parts)
+}
+
+func Intersect[T any](parts ...*Table[T]) *SetQuery[T] {
+	return newSetQuery("INTERSECT", parts)
+}
+
+func Except[T any](parts ...*Table[T]) *SetQuery[T] {
+	return newSetQuery("EXCEPT", parts)
+}
+
+func newSetQuery[T any](operator string, parts []*Table[T]) *SetQuery[T] {
+	if len(parts) < 2 {
+		panic(fmt.Sprintf("%s: at least two selects are required, got %d", operator, len(parts)))
+	}
+
+	columns := parts[0].ResultColumns()
+
+	for i, part := range parts {
+		if len(part.orderBy) > 0 || part.limit != nil {
+			panic(fmt.Sprintf("%s: select %d of %s has its own ORDER BY or LIMIT, apply them to the combined result instead", operator, i, part.tableName))
+		}
+
+		partColumns := part.ResultColumns()
+		if len(partColumns) != len(columns) {
+			panic(fmt.Sprintf("%s: select %d of %s must select %d columns, got %d", operator, i, part.tableName, len(columns), len(partColumns)))
+		}
+
+		for j, column := range columns {
+			if !column.accepts(partColumns[j].typ) {
+				panic(fmt.Sprintf("%s: incorrect type for column %d of %s. Need %s, got %s", operator, j, part.tableName, column.typ, partColumns[j].typ))
+			}
+		}
+	}
+
+	return &SetQuery[T]{
+		operator: operator,
+		parts:    parts,
+	}
+}
+
+// Order the combined result by one of its columns
+func (s *SetQuery[T]) AddOrderByClause(columnName string, sortDirection SortDirection) *SetQuery[T] {
+	s.parts[0].AssertColumnExists(columnName)
+
+	for _, column := range s.parts[0].selectedColumns() {
+		if column == columnName {
+			s.orderBy = append(s.orderBy, NewOrderByClause(columnName, sortDirection))
+			return s
+		}
+	}
+
+	panic(fmt.Sprintf("%s: cannot order by %s, it is not selected", s.operator, columnName))
+}
+
+func (s *SetQuery[T]) Limit(rowCount int64, offset int64) *SetQuery[T] {
+	s.limit = NewLimitClause(rowCount, offset)
+
+	return s
+}
+
+// CTEs attached to the parts are defined once, before the combined query, as WITH is not valid within a
+// set operation.
+func (s *SetQuery[T]) BuildSelect(params *ParamList) string {
+	with := buildWith(s.ctes(params), params)
+
+	built := make([]string, 0, len(s.parts))
+	for _, part := range s.parts {
+		built = append(built, part.buildSelectBody(params))
+	}
+
+	orderClause := BuildOrderBy(s.orderBy, params)
+
+	limitClause := ""
+	if s.limit != nil {
+		limitClause = s.limit.Build(params)
+	}
+
+	return fmt.Sprint(with, strings.Join(built, fmt.Sprintf(" %s ", s.operator)), orderClause, limitClause)
+}
+
+// The CTEs attached to every part, each once. Different CTEs sharing a name are recorded as an error.
+func (s *SetQuery[T]) ctes(params *ParamList) []*CTE {
+	ctes := []*CTE{}
+	named := map[string]*CTE{}
+
+	for _, part := range s.parts {
+		for _, c := range part.ctes {
+			if other, ok := named[c.name]; ok {
+				if other != c {
+					params.RecordError(fmt.Errorf("%s: parts attach different CTEs named %s", s.operator, c.name))
+				}
+
+				continue
+			}
+
+			named[c.name] = c
+			ctes = append(ctes, c)
+		}
+	}
+
+	return ctes
+}
+
+func (s *SetQuery[T]) ResultColumns() []*Column {
+	return s.parts[0].ResultColumns()
+}
+
+// Build the combined query. Rows are scanned into the receivers a provides for the columns of the first
+// part.
+func (s *SetQuery[T]) Build(a Accumulator[T], dialect Dialect) *Query[T] {
+	q, err := s.TryBuild(a, dialect)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return q
+}
+
+// Build the combined query, returning an error rather than panicking if the query is invalid
+func (s *SetQuery[T]) TryBuild(a Accumulator[T], dialect Dialect) (*Query[T], error) {
+	first := s.parts[0]
+	receivers := a.GetColumnReceiverMap()
+
+	columnErrors := first.checkReceivers(receivers)
+	columns := first.selectedColumns()
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("%s: select 0 of %s selects no columns, set receivers or use Select", s.operator, first.tableName)
+	}
+
+	scanList := make([]interface{}, 0, len(columns))
+
+	for _, columnName := range columns {
+		receiver, ok := receivers[columnName]
+		if !ok {
+			columnErrors[columnName] = "no receiver for selected column"
+			continue
+		}
+
+		scanList = append(scanList, first.fields[columnName].scanTarget(receiver))
+	}
+
+	if len(columnErrors) > 0 {
+		return nil, errors.New(formatColumnErrors(columnErrors))
+	}
+
+	paramList := NewParamList(dialect)
+
+	query := s.BuildSelect(paramList)
+	paramList.recordUnboundParams()
+
+	if err := paramList.Err(); err != nil {
+		return nil, fmt.Errorf("Build: %w", err)
+	}
+
+	return &Query[T]{
+		query:    query,
+		scanList: scanList,
+		params:   paramList.GetParamList(),
+		dialect:  dialect,
+
+		table:     first.tableName,
+		statement: "SELECT",
+
+		accumulator: a,
+	}, nil
+}
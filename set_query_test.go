@@ -0,0 +1,133 @@
+package sqb_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	sqb "github.com/themanciraptor/SQb"
+)
+
+func nameAccumulator() sqb.Accumulator[exampleResult] {
+	return sqb.NewAccumulator(func(r *exampleResult) map[string]interface{} {
+		return map[string]interface{}{
+			"cool":           &r.Name,
+			"number_of_star": &r.NumStars,
+		}
+	})
+}
+
+func Test_SetQuery_BuildsCorrectly(t *testing.T) {
+	type testCase struct {
+		description string
+		build       func(a, b *sqb.Table[exampleResult]) *sqb.SetQuery[exampleResult]
+		operator    string
+	}
+
+	testCases := []testCase{
+		{"union", func(a, b *sqb.Table[exampleResult]) *sqb.SetQuery[exampleResult] { return sqb.Union(a, b) }, "UNION"},
+		{"union all", func(a, b *sqb.Table[exampleResult]) *sqb.SetQuery[exampleResult] { return sqb.UnionAll(a, b) }, "UNION ALL"},
+		{"intersect", func(a, b *sqb.Table[exampleResult]) *sqb.SetQuery[exampleResult] { return sqb.Intersect(a, b) }, "INTERSECT"},
+		{"except", func(a, b *sqb.Table[exampleResult]) *sqb.SetQuery[exampleResult] { return sqb.Except(a, b) }, "EXCEPT"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			a := sqb.NewTable[exampleResult]("exampleTable", sqb.Psql(), &exampleModel{}).
+				Select("cool", "number_of_star").
+				ColumnEquals("is_true_true", true)
+			b := sqb.NewTable[exampleResult]("orders", sqb.Psql(), &exampleOrderModel{}).
+				Select("owner", "stars").
+				ColumnEquals("status", "open")
+
+			q := tc.build(a, b).
+				AddOrderByClause("number_of_star", sqb.Descending).
+				Limit(10, 20).
+				Build(nameAccumulator(), sqb.Psql())
+
+			assert.Equal(t, "SELECT cool, number_of_star FROM exampleTable WHERE is_true_true = $1 "+
+				tc.operator+
+				" SELECT owner, stars FROM orders WHERE status = $2 ORDER BY number_of_star DESC LIMIT $3 OFFSET $4", q.GetQuery())
+			assert.Equal(t, []interface{}{true, "open", int64(10), int64(20)}, q.GetParams())
+			assert.Len(t, q.GetScanList(), 2)
+		})
+	}
+}
+
+func Test_SetQuery_Panics(t *testing.T) {
+	table := func() *sqb.Table[exampleResult] {
+		return sqb.NewTable[exampleResult]("exampleTable", sqb.Psql(), &exampleModel{})
+	}
+	orders := func() *sqb.Table[exampleResult] {
+		return sqb.NewTable[exampleResult]("orders", sqb.Psql(), &exampleOrderModel{})
+	}
+
+	type testCase struct {
+		description string
+		build       func()
+		panicMsg    string
+	}
+
+	testCases := []testCase{
+		{
+			description: "when fewer than two selects are combined",
+			build:       func() { sqb.Union(table().Select("cool")) },
+			panicMsg:    "UNION: at least two selects are required, got 1",
+		},
+		{
+			description: "when selects have different column counts",
+			build:       func() { sqb.Union(table().Select("cool"), orders().Select("owner", "stars")) },
+			panicMsg:    "UNION: select 1 of orders must select 1 columns, got 2",
+		},
+		{
+			description: "when selects have different column kinds",
+			build:       func() { sqb.Except(table().Select("cool"), orders().Select("stars")) },
+			panicMsg:    "EXCEPT: incorrect type for column 0 of orders. Need string, got int64",
+		},
+		{
+			description: "when a select has its own limit",
+			build:       func() { sqb.Intersect(table().Select("cool"), orders().Select("owner").Limit(1, 0)) },
+			panicMsg:    "INTERSECT: select 1 of orders has its own ORDER BY or LIMIT, apply them to the combined result instead",
+		},
+		{
+			description: "when ordering by a column which is not selected",
+			build: func() {
+				sqb.Union(table().Select("cool"), orders().Select("owner")).AddOrderByClause("number_of_star", sqb.Ascending)
+			},
+			panicMsg: "UNION: cannot order by number_of_star, it is not selected",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			assert.PanicsWithValue(t, tc.panicMsg, tc.build)
+		})
+	}
+}
+
+func Test_SetQuery_HoistsCTEs(t *testing.T) {
+	active := sqb.NewCTE("active", sqb.NewTable[any]("exampleTable", sqb.Psql(), &exampleModel{}).
+		Select("cool", "number_of_star").
+		ColumnEquals("is_true_true", true))
+
+	a := sqb.FromCTE[exampleResult](active).Select("cool", "number_of_star").With(active)
+	b := sqb.FromCTE[exampleResult](active).Select("cool", "number_of_star").ColumnEquals("number_of_star", int64(5)).With(active)
+
+	q := sqb.Union(a, b).Build(nameAccumulator(), sqb.Psql())
+
+	assert.Equal(t, "WITH active AS (SELECT cool, number_of_star FROM exampleTable WHERE is_true_true = $1) "+
+		"SELECT cool, number_of_star FROM active UNION SELECT cool, number_of_star FROM active WHERE number_of_star = $2", q.GetQuery())
+	assert.Equal(t, []interface{}{true, int64(5)}, q.GetParams())
+
+	other := sqb.NewCTE("active", sqb.NewTable[any]("orders", sqb.Psql(), &exampleOrderModel{}).Select("owner", "stars"))
+	_, err := sqb.Union(a, sqb.FromCTE[exampleResult](other).Select("owner", "stars").With(other)).TryBuild(nameAccumulator(), sqb.Psql())
+	assert.EqualError(t, err, "Build: UNION: parts attach different CTEs named active")
+}
+
+func Test_SetQuery_RequiresColumns(t *testing.T) {
+	a := sqb.NewTable[exampleResult]("exampleTable", sqb.Psql(), &exampleModel{})
+	b := sqb.NewTable[exampleResult]("orders", sqb.Psql(), &exampleOrderModel{})
+
+	q, err := sqb.Union(a, b).TryBuild(nameAccumulator(), sqb.Psql())
+	assert.Nil(t, q)
+	assert.EqualError(t, err, "UNION: select 0 of exampleTable selects no columns, set receivers or use Select")
+}
@@ -0,0 +1,156 @@
+package sqb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+)
+
+/*
+	SlowQueryHook captures the plan of queries which run longer than a threshold. The plan is fetched by
+	running the dialect's EXPLAIN statement, which must implement Explainer, with the same SQL and params
+	through the same runner, without hooks. A PreparedRunner runs it without preparing a statement, so that
+	plans don't evict cached queries. The query is not run again, so writes are safe to explain. Failed
+	queries are not explained.
+
+	Explaining takes a round trip to the database, so a sample rate limits how many slow queries are
+	explained, and the EXPLAIN statement has its own timeout. It runs before Query.Run returns.
+*/
+
+type SlowQuery struct {
+	Event QueryEvent
+
+	// The plan returned by EXPLAIN, or the error fetching it
+	Plan json.RawMessage
+	Err  error
+}
+
+// Runners which cache statements, whose one-off queries should skip the cache
+type unpreparedRunner interface {
+	runUnprepared(ctx context.Context, query string, params []interface{}) (tempRows, func(ctx context.Context), error)
+}
+
+type SlowQueryHook struct {
+	threshold  time.Duration
+	sampleRate float64
+	timeout    time.Duration
+	callback   func(ctx context.Context, slow SlowQuery)
+}
+
+type SlowQueryOption func(*SlowQueryHook)
+
+// Explain the given fraction of slow queries, between 0 and 1. Defaults to 1.
+func WithSampleRate(rate float64) SlowQueryOption {
+	return func(h *SlowQueryHook) {
+		h.sampleRate = rate
+	}
+}
+
+// Give up on EXPLAIN after timeout. Defaults to 5 seconds.
+func WithExplainTimeout(timeout time.Duration) SlowQueryOption {
+	return func(h *SlowQueryHook) {
+		h.timeout = timeout
+	}
+}
+
+// Pass slow queries to callback instead of logging them
+func WithSlowQueryCallback(callback func(ctx context.Context, slow SlowQuery)) SlowQueryOption {
+	return func(h *SlowQueryHook) {
+		h.callback = callback
+	}
+}
+
+// Log slow queries with logger at slog.LevelWarn. Slow queries are logged with slog.Default() unless a
+// logger or callback is given.
+func WithSlowQueryLogger(logger *slog.Logger) SlowQueryOption {
+	return func(h *SlowQueryHook) {
+		h.callback = func(ctx context.Context, slow SlowQuery) {
+			attrs := []slog.Attr{
+				slog.String("sql", slow.Event.Query),
+				slog.String("table", slow.Event.Table),
+				slog.String("statement", slow.Event.Statement),
+				slog.Duration("duration", slow.Event.Duration),
+			}
+
+			if slow.Err != nil {
+				attrs = append(attrs, slog.String("explain_error", slow.Err.Error()))
+			} else {
+				attrs = append(attrs, slog.Any("plan", slow.Plan))
+			}
+
+			logger.LogAttrs(ctx, slog.LevelWarn, "slow query", attrs...)
+		}
+	}
+}
+
+func NewSlowQueryHook(threshold time.Duration, options ...SlowQueryOption) *SlowQueryHook {
+	h := &SlowQueryHook{
+		threshold:  threshold,
+		sampleRate: 1,
+		timeout:    5 * time.Second,
+	}
+
+	WithSlowQueryLogger(slog.Default())(h)
+
+	for _, option := range options {
+		option(h)
+	}
+
+	return h
+}
+
+func (h *SlowQueryHook) BeforeQuery(ctx context.Context, event *QueryEvent) context.Context {
+	return ctx
+}
+
+func (h *SlowQueryHook) AfterQuery(ctx context.Context, event *QueryEvent) {
+	if event.Err != nil || event.Duration < h.threshold || rand.Float64() >= h.sampleRate {
+		return
+	}
+
+	slow := SlowQuery{Event: *event}
+	slow.Plan, slow.Err = h.explain(ctx, event)
+
+	h.callback(ctx, slow)
+}
+
+func (h *SlowQueryHook) explain(ctx context.Context, event *QueryEvent) (json.RawMessage, error) {
+	explainer, ok := DialectAs[Explainer](event.dialect)
+	if !ok {
+		return nil, fmt.Errorf("SlowQueryHook: dialect %T cannot explain queries, implement Explainer", event.dialect)
+	}
+
+	// The query's context may have been cancelled by the same timeout that made it slow
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), h.timeout)
+	defer cancel()
+
+	run := event.runner.RunQuery
+	if unprepared, ok := event.runner.(unpreparedRunner); ok {
+		run = unprepared.runUnprepared
+	}
+
+	res, closer, err := run(ctx, explainer.ExplainQuery(event.Query), event.params)
+	if err != nil {
+		return nil, errors.Join(err, errors.New("SlowQueryHook: failed to explain query"))
+	}
+	defer closer(ctx)
+
+	if !res.Next() {
+		if errRows, ok := res.(interface{ Err() error }); ok && errRows.Err() != nil {
+			return nil, errors.Join(errRows.Err(), errors.New("SlowQueryHook: failed to explain query"))
+		}
+
+		return nil, errors.New("SlowQueryHook: EXPLAIN returned no plan")
+	}
+
+	var plan []byte
+	if err := res.Scan(&plan); err != nil {
+		return nil, errors.Join(err, errors.New("SlowQueryHook: failed to scan plan"))
+	}
+
+	return json.RawMessage(plan), nil
+}
@@ -0,0 +1,141 @@
+package sqb_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	sqb "github.com/themanciraptor/SQb"
+	"github.com/themanciraptor/SQb/internal/fakedb"
+)
+
+const examplePlan = `[{"Plan": {"Node Type": "Seq Scan", "Relation Name": "exampleTable"}}]`
+
+// Answers EXPLAIN with examplePlan and other queries with a row
+func explainingHandler(t *testing.T) fakedb.Handler {
+	return func(query string, args []driver.Value) ([]string, [][]driver.Value, error) {
+		if strings.HasPrefix(query, "EXPLAIN") {
+			assert.Equal(t, []driver.Value{int64(3)}, args)
+			return []string{"QUERY PLAN"}, [][]driver.Value{{[]byte(examplePlan)}}, nil
+		}
+
+		return []string{"cool"}, [][]driver.Value{{"doom"}}, nil
+	}
+}
+
+func Test_SlowQueryHook_ExplainsSlowQueries(t *testing.T) {
+	db, fake := fakedb.New(explainingHandler(t))
+
+	var slow []sqb.SlowQuery
+	hook := sqb.NewSlowQueryHook(0, sqb.WithSlowQueryCallback(func(ctx context.Context, s sqb.SlowQuery) {
+		slow = append(slow, s)
+	}))
+
+	q, acc := buildExampleQuery()
+	assert.NoError(t, q.Run(context.Background(), sqb.WithHooks(sqb.NewPreparedRunner(db, 2), hook)))
+	assert.Len(t, acc.GetResults(), 1)
+
+	assert.Len(t, slow, 1)
+	assert.NoError(t, slow[0].Err)
+	assert.JSONEq(t, examplePlan, string(slow[0].Plan))
+	assert.Equal(t, "SELECT cool FROM exampleTable WHERE number_of_star = $1", slow[0].Event.Query)
+	assert.Equal(t, []string{
+		"SELECT cool FROM exampleTable WHERE number_of_star = $1",
+		"EXPLAIN (FORMAT JSON) SELECT cool FROM exampleTable WHERE number_of_star = $1",
+	}, fake.Executed())
+}
+
+func Test_SlowQueryHook_SkipsFastAndUnsampledQueries(t *testing.T) {
+	for name, hook := range map[string]*sqb.SlowQueryHook{
+		"fast":      sqb.NewSlowQueryHook(time.Hour),
+		"unsampled": sqb.NewSlowQueryHook(0, sqb.WithSampleRate(0)),
+	} {
+		t.Run(name, func(t *testing.T) {
+			db, fake := fakedb.New(explainingHandler(t))
+
+			q, _ := buildExampleQuery()
+			assert.NoError(t, q.Run(context.Background(), sqb.WithHooks(sqb.NewPreparedRunner(db, 2), hook)))
+			assert.Equal(t, []string{"SELECT cool FROM exampleTable WHERE number_of_star = $1"}, fake.Executed())
+		})
+	}
+}
+
+func Test_SlowQueryHook_SkipsTheStatementCache(t *testing.T) {
+	db, _ := fakedb.New(explainingHandler(t))
+	runner := sqb.NewPreparedRunner(db, 1)
+	hooked := sqb.WithHooks(runner, sqb.NewSlowQueryHook(0, sqb.WithSlowQueryCallback(func(ctx context.Context, s sqb.SlowQuery) {
+		assert.NoError(t, s.Err)
+	})))
+
+	for range 2 {
+		q, _ := buildExampleQuery()
+		assert.NoError(t, q.Run(context.Background(), hooked))
+	}
+
+	// EXPLAIN would evict the query from a cache of one statement
+	assert.Equal(t, int64(1), runner.Misses())
+	assert.Equal(t, int64(1), runner.Hits())
+}
+
+func Test_SlowQueryHook_SkipsFailedQueries(t *testing.T) {
+	db, fake := fakedb.New(func(query string, args []driver.Value) ([]string, [][]driver.Value, error) {
+		return nil, nil, errors.New("canceling statement due to statement timeout")
+	})
+
+	called := false
+	hook := sqb.NewSlowQueryHook(0, sqb.WithSlowQueryCallback(func(ctx context.Context, s sqb.SlowQuery) {
+		called = true
+	}))
+
+	q, _ := buildExampleQuery()
+	assert.Error(t, q.Run(context.Background(), sqb.WithHooks(sqb.NewPreparedRunner(db, 2), hook)))
+	assert.False(t, called)
+	assert.Equal(t, []string{"SELECT cool FROM exampleTable WHERE number_of_star = $1"}, fake.Executed())
+}
+
+func Test_SlowQueryHook_LogsPlans(t *testing.T) {
+	db, _ := fakedb.New(explainingHandler(t))
+
+	var logs bytes.Buffer
+	hook := sqb.NewSlowQueryHook(0, sqb.WithSlowQueryLogger(slog.New(slog.NewJSONHandler(&logs, nil))))
+
+	q, _ := buildExampleQuery()
+	assert.NoError(t, q.Run(context.Background(), sqb.WithHooks(sqb.NewPreparedRunner(db, 2), hook)))
+
+	var logged map[string]any
+	assert.NoError(t, json.Unmarshal(logs.Bytes(), &logged))
+	assert.Equal(t, "WARN", logged["level"])
+	assert.Equal(t, "slow query", logged["msg"])
+	assert.Equal(t, "exampleTable", logged["table"])
+
+	plan, _ := json.Marshal(logged["plan"])
+	assert.JSONEq(t, examplePlan, string(plan))
+}
+
+func Test_SlowQueryHook_RequiresExplainer(t *testing.T) {
+	db, _ := fakedb.New(func(query string, args []driver.Value) ([]string, [][]driver.Value, error) {
+		return []string{"cool"}, nil, nil
+	})
+
+	var slow []sqb.SlowQuery
+	hook := sqb.NewSlowQueryHook(0, sqb.WithSlowQueryCallback(func(ctx context.Context, s sqb.SlowQuery) {
+		slow = append(slow, s)
+	}))
+
+	acc := NewResultAccumulator()
+	q := sqb.NewTable[exampleResult]("exampleTable", exampleDialect{}, &exampleModel{}).
+		Select("cool").
+		LoadReceiversFromAccumulator(acc).
+		Build(acc, exampleDialect{})
+
+	assert.NoError(t, q.Run(context.Background(), sqb.WithHooks(sqb.NewPreparedRunner(db, 2), hook)))
+	assert.Len(t, slow, 1)
+	assert.ErrorContains(t, slow[0].Err, "cannot explain queries")
+}
@@ -1,6 +1,7 @@
 package sqb
 
 import (
+	"database/sql"
 	"fmt"
 	"reflect"
 	"sort"
@@ -9,6 +10,22 @@ import (
 	"github.com/lib/pq"
 )
 
+// A select statement which can be embedded within another query, e.g. as a subquery
+type Selectable interface {
+	BuildSelect(params *ParamList) string
+	ResultColumns() []*Column
+}
+
+// A reference to a table whose columns can be referred to from another query
+type TableRef interface {
+	TableName() string
+	AssertColumnExists(columnName string)
+	GetColumn(columnName string) *Column
+
+	// Every column of the table, in model field order
+	Columns() []*Column
+}
+
 /*
 Table's hold information about a specific database table. They are essentially helper structs
 developer can use to easily build queries against our database.
@@ -20,14 +37,40 @@ type Table[T any] struct {
 	// Map table columns to a value receivers.
 	fields map[string]*Column
 
+	// The dialect the table's columns were read for, used by Verify
+	dialect Dialect
+
+	// The table model, and its column names in field order. Not set for tables without a model, such as CTEs
+	modelType   reflect.Type
+	columnOrder []string
+
 	// The filter clause applied to the table
 	filter *CompoundClause
 
 	// Ordering Applied to Table
-	orderBy *CompoundClause
+	orderBy []*OrderByClause
 
 	// Limit
 	limit *LimitClause
+
+	// Common table expressions defined before the select statement
+	ctes []*CTE
+
+	// Sources joined to the table
+	joins []Clause
+
+	// Values bound to the table's scope columns, and whether the table may be queried without them
+	scopes map[string]interface{}
+	admin  bool
+
+	// Problems found while adding filters, which prevent the query from being built
+	errs []error
+
+	// Columns explicitly selected, in order. When empty, every column with a receiver is selected
+	selected []string
+
+	// The accumulator receivers were last loaded from, used when compiling templates
+	accumulator Accumulator[T]
 }
 
 // Set receiver for a particular table column. The column must exist on the table.
@@ -39,15 +82,15 @@ func (t *Table[T]) SetColumnReceiver(columnName string, scanTo interface{}) *Tab
 	if pair, ok := t.fields[columnName]; ok {
 		indirectVal := reflect.Indirect(reflect.ValueOf(scanTo))
 
-		if indirectVal.Kind() != pair.kind && indirectVal.Type() != nullType(pair.kind) {
-			panic(fmt.Sprintf("SetField: attempted to scan to invalid type for field: cannot assign %v to: %v", reflect.TypeOf(scanTo).Kind(), pair.kind))
+		if !pair.accepts(indirectVal.Type()) {
+			panic(fmt.Sprintf("SetField: attempted to scan to invalid type for field: cannot assign %v to: %v", indirectVal.Type(), pair.typ))
 		}
 
-		if reflect.Indirect(reflect.ValueOf(scanTo)).Kind() == reflect.Slice {
-			t.fields[columnName].SetReceiver(pq.Array(scanTo))
+		if !canScan(indirectVal.Type()) {
+			panic(fmt.Sprintf("SetField: cannot scan to %v, implement sql.Scanner or use RegisterType", indirectVal.Type()))
 		}
 
-		t.fields[columnName].SetReceiver(scanTo)
+		t.fields[columnName].SetReceiver(pair.scanTarget(scanTo))
 		return t
 	}
 
@@ -55,9 +98,24 @@ func (t *Table[T]) SetColumnReceiver(columnName string, scanTo interface{}) *Tab
 }
 
 func (t *Table[T]) LoadReceiversFromAccumulator(a Accumulator[T]) *Table[T] {
-	columnErrors := map[string]interface{}{}
+	columnErrors := t.checkReceivers(a.GetColumnReceiverMap())
+
+	if len(columnErrors) > 0 {
+		panic(formatColumnErrors(columnErrors))
+	}
+
+	for columnName, receiver := range a.GetColumnReceiverMap() {
+		t.fields[columnName].SetReceiver(t.fields[columnName].scanTarget(receiver))
+	}
 
-	receivers := a.GetColumnReceiverMap()
+	t.accumulator = a
+
+	return t
+}
+
+// Returns a description of the problem with each receiver that cannot be used for its column
+func (t *Table[T]) checkReceivers(receivers map[string]interface{}) map[string]interface{} {
+	columnErrors := map[string]interface{}{}
 
 	for columnName, receiver := range receivers {
 		if reflect.TypeOf(receiver).Kind() != reflect.Ptr {
@@ -67,36 +125,49 @@ func (t *Table[T]) LoadReceiversFromAccumulator(a Accumulator[T]) *Table[T] {
 		if pair, ok := t.fields[columnName]; ok {
 			indirectVal := reflect.Indirect(reflect.ValueOf(receiver))
 
-			if indirectVal.Kind() != pair.kind && indirectVal.Type() != nullType(pair.kind) {
-				columnErrors[columnName] = fmt.Sprintf("Invalid type for field: cannot assign %v to: %v", reflect.TypeOf(receiver).Kind(), pair.kind)
-				continue
+			if !pair.accepts(indirectVal.Type()) {
+				columnErrors[columnName] = fmt.Sprintf("Invalid type for field: cannot assign %v to: %v", indirectVal.Type(), pair.typ)
+			} else if !canScan(indirectVal.Type()) {
+				columnErrors[columnName] = fmt.Sprintf("Cannot scan to %v, implement sql.Scanner or use RegisterType", indirectVal.Type())
 			}
-
-			if reflect.Indirect(reflect.ValueOf(receiver)).Kind() == reflect.Slice {
-				t.fields[columnName].SetReceiver(pq.Array(receiver))
-				continue
-			}
-
-			t.fields[columnName].SetReceiver(receiver)
 		} else {
 			columnErrors[columnName] = "Column not included in table"
 		}
 	}
 
-	if len(columnErrors) > 0 {
-		e := strings.Builder{}
+	return columnErrors
+}
 
-		for c, v := range columnErrors {
-			e.WriteString(fmt.Sprintf("%s: %s\n", c, v))
-		}
+func formatColumnErrors(columnErrors map[string]interface{}) string {
+	e := strings.Builder{}
 
-		panic(e.String())
+	for c, v := range columnErrors {
+		e.WriteString(fmt.Sprintf("%s: %s\n", c, v))
 	}
 
-	return t
+	return e.String()
 }
 
-func NewTable[T any](tableName string, dialect Dialect, model interface{}) *Table[T] {
+// Slices and registered types must be adapted before the driver can scan into them. Byte slices and
+// sql.Scanner implementations are scanned directly.
+func scanTarget(receiver interface{}) interface{} {
+	if _, ok := receiver.(sql.Scanner); ok {
+		return receiver
+	}
+
+	t := reflect.Indirect(reflect.ValueOf(receiver)).Type()
+	if adaptor, ok := lookupRegisteredType(t); ok {
+		return &registeredScanner{dest: receiver, scan: adaptor.scan}
+	}
+
+	if t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8 {
+		return pq.Array(receiver)
+	}
+
+	return receiver
+}
+
+func NewTable[T any](tableName string, dialect Dialect, model interface{}, options ...TableOption) *Table[T] {
 	if reflect.TypeOf(model).Kind() != reflect.Ptr {
 		panic("QueryBuilder: Table model must be pointer to struct type")
 	}
@@ -107,19 +178,28 @@ func NewTable[T any](tableName string, dialect Dialect, model interface{}) *Tabl
 		panic("QueryBuilder: Table model must be pointer to struct type")
 	}
 
+	o := tableOptions{}
+	for _, option := range options {
+		option(&o)
+	}
+
+	// Provide default columns based on the table model
+	return newTable[T](tableName, dialect, modelValue.Type(), modelColumns(modelValue.Type(), dialect, o))
+}
+
+func newTable[T any](tableName string, dialect Dialect, modelType reflect.Type, columns []*Column) *Table[T] {
 	table := &Table[T]{
 		tableName: tableName,
+		dialect:   dialect,
 		fields:    map[string]*Column{},
 		filter:    NewCompoundClause("AND"),
-		orderBy:   NewCompoundClause(","),
+		modelType: modelType,
+		scopes:    map[string]interface{}{},
 	}
 
-	// Provide default columns based on the table model
-	for i := 0; i < modelValue.NumField(); i++ {
-		kind := reflect.TypeOf(model).Elem().Field(i).Type.Kind()
-
-		c := reflect.TypeOf(model).Elem().Field(i).Tag.Get(dialect.StructTag())
-		table.fields[c] = NewColumn(kind)
+	for _, column := range columns {
+		table.fields[column.name] = column
+		table.columnOrder = append(table.columnOrder, column.name)
 	}
 
 	return table
@@ -137,46 +217,249 @@ func NewTable[T any](tableName string, dialect Dialect, model interface{}) *Tabl
 //	context. This support does not rely on the query builder per se. But
 //	having the query builder already will make implementation easier.
 func (t *Table[T]) Build(a Accumulator[T], dialect Dialect) *Query[T] {
-	selectedFields := make([]string, 0, len(t.fields))
-	scanList := make([]interface{}, 0, len(t.fields))
+	q, err := t.TryBuild(a, dialect)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return q
+}
+
+// Build the query, returning an error rather than panicking if the query is invalid for the dialect
+func (t *Table[T]) TryBuild(a Accumulator[T], dialect Dialect) (*Query[T], error) {
+	return t.tryBuild(a, dialect, false)
+}
+
+// Build the query, leaving named params in place when compiling a Template
+func (t *Table[T]) tryBuild(a Accumulator[T], dialect Dialect, compiling bool) (*Query[T], error) {
+	selectedFields := t.selectedColumns()
+	scanList := make([]interface{}, 0, len(selectedFields))
 	paramList := NewParamList(dialect)
-	filters := ""
 
-	if t.filter.NumClauses() > 0 {
-		filters = fmt.Sprint(` WHERE `, t.filter.Build(paramList))
+	for _, columnName := range selectedFields {
+		receiver := t.fields[columnName].receiver
+		if receiver == nil {
+			return nil, fmt.Errorf("Build: no receiver set for selected column %s", columnName)
+		}
+
+		scanList = append(scanList, receiver)
 	}
 
-	keys := make([]string, 0, len(t.fields))
-	for key := range t.fields {
-		keys = append(keys, key)
+	query := t.BuildSelect(paramList)
+	if !compiling {
+		paramList.recordUnboundParams()
 	}
 
-	sort.Strings(keys)
+	if err := paramList.Err(); err != nil {
+		return nil, fmt.Errorf("Build: %w", err)
+	}
 
-	for _, columnName := range keys {
-		sp := t.fields[columnName]
+	return &Query[T]{
+		query:    query,
+		scanList: scanList,
+		params:   paramList.GetParamList(),
+		dialect:  dialect,
+
+		table:     t.tableName,
+		statement: "SELECT",
+
+		accumulator: a,
+	}, nil
+}
+
+// Build the select statement for the table, recording its params in params. This allows the table to be
+// embedded in another query, sharing its params.
+func (t *Table[T]) BuildSelect(params *ParamList) string {
+	with := buildWith(t.ctes, params)
+
+	return with + t.buildSelectBody(params)
+}
+
+// Build the select statement without its WITH clause, for queries which define the CTEs themselves
+func (t *Table[T]) buildSelectBody(params *ParamList) string {
+	selectedFields := t.selectedColumns()
 
-		if sp.receiver != nil {
-			selectedFields = append(selectedFields, columnName)
-			scanList = append(scanList, sp.receiver)
+	joins := ""
+	for _, j := range t.joins {
+		joins += j.Build(params)
+	}
+
+	// Joined sources may share column names, so qualify the columns selected from this table
+	if len(t.joins) > 0 {
+		qualified := make([]string, 0, len(selectedFields))
+		for _, columnName := range selectedFields {
+			qualified = append(qualified, t.tableName+"."+columnName)
 		}
+
+		selectedFields = qualified
 	}
 
+	filters := t.buildWhere(params)
+
+	orderClause := t.qualify(BuildOrderBy(t.orderBy, params))
+
 	limitClause := ""
 	if t.limit != nil {
-		limitClause = t.limit.Build()
+		limitClause = t.limit.Build(params)
 	}
 
-	orderClause := ""
-	if t.orderBy.NumClauses() > 0 {
-		orderClause = t.orderBy.Build(paramList)
+	selectList := strings.Join(selectedFields, ", ")
+	if len(selectedFields) == 0 {
+		selectList = "1"
 	}
 
-	return &Query[T]{
-		query:    fmt.Sprint(`SELECT `, strings.Join(selectedFields, ", "), ` FROM `, t.tableName, filters, orderClause, limitClause),
-		scanList: scanList,
-		params:   paramList.GetParamList(),
+	return fmt.Sprint(`SELECT `, selectList, ` FROM `, t.tableName, joins, filters, orderClause, limitClause)
+}
 
-		accumulator: a,
+// Select the given columns, in order, rather than every column with a receiver. Mostly useful for
+// subqueries, which have no receivers.
+func (t *Table[T]) Select(columnNames ...string) *Table[T] {
+	for _, columnName := range columnNames {
+		t.AssertColumnExists(columnName)
+	}
+
+	t.selected = columnNames
+
+	return t
+}
+
+// The columns the table's select statement produces, in order
+func (t *Table[T]) ResultColumns() []*Column {
+	selectedFields := t.selectedColumns()
+	columns := make([]*Column, 0, len(selectedFields))
+
+	for _, columnName := range selectedFields {
+		columns = append(columns, t.fields[columnName])
+	}
+
+	return columns
+}
+
+func (t *Table[T]) TableName() string {
+	return t.tableName
+}
+
+// The primary key columns, in model field order
+func (t *Table[T]) PrimaryKey() []string {
+	pk := []string{}
+
+	for _, columnName := range t.columnOrder {
+		if t.fields[columnName].primaryKey {
+			pk = append(pk, columnName)
+		}
+	}
+
+	return pk
+}
+
+func (t *Table[T]) Columns() []*Column {
+	columns := make([]*Column, 0, len(t.columnOrder))
+
+	for _, columnName := range t.columnOrder {
+		columns = append(columns, t.fields[columnName])
+	}
+
+	return columns
+}
+
+func (t *Table[T]) GetColumn(columnName string) *Column {
+	t.AssertColumnExists(columnName)
+
+	return t.fields[columnName]
+}
+
+// Attach common table expressions to the table's select statement. Tables created with FromCTE can then be
+// used as sources of the query, its joins, or its subqueries.
+func (t *Table[T]) With(ctes ...*CTE) *Table[T] {
+	t.ctes = append(t.ctes, ctes...)
+
+	return t
+}
+
+// Inner join a source on columnName = sourceColumnName. Only columns of this table are selected. The source
+// contributes its name, and when it is a Table its scopes and filters, which are added to the join condition.
+// A scoped source must be scoped or Admin too. Joins, CTEs, ORDER BY and LIMIT of a source Table are build
+// errors: attach CTEs to this table with With.
+func (t *Table[T]) Join(source TableRef, columnName string, sourceColumnName string) *Table[T] {
+	column := t.GetColumn(columnName)
+	sourceColumn := source.GetColumn(sourceColumnName)
+
+	if !column.accepts(sourceColumn.typ) {
+		panic(fmt.Sprintf("Incorrect type for join column. Need %s, got %s", column.typ, sourceColumn.typ))
 	}
+
+	join := NewJoinClause(
+		"JOIN",
+		source.TableName(),
+		t.tableName+"."+columnName,
+		source.TableName()+"."+sourceColumnName,
+	)
+
+	if scoped, ok := source.(joinSource); ok {
+		join.sourceFilters = scoped.buildJoinFilters
+	}
+
+	t.joins = append(t.joins, join)
+
+	return t
+}
+
+// Sources joined to a table add their scope filters and filters to the join condition
+type joinSource interface {
+	buildJoinFilters(params *ParamList) string
+}
+
+// The table's scope filters and filters, qualified by its name, for when it is joined to another table
+func (t *Table[T]) buildJoinFilters(params *ParamList) string {
+	if len(t.joins) > 0 || len(t.ctes) > 0 || len(t.orderBy) > 0 || t.limit != nil {
+		params.RecordError(fmt.Errorf("Join: source %s has joins, CTEs, ORDER BY or LIMIT, which cannot be joined", t.tableName))
+	}
+
+	return strings.ReplaceAll(t.filters(params).Build(params), columnMarker, t.tableName+".")
+}
+
+// Columns in the order they are selected and scanned
+func (t *Table[T]) selectedColumns() []string {
+	if len(t.selected) > 0 {
+		return t.selected
+	}
+
+	keys := make([]string, 0, len(t.fields))
+	for key := range t.fields {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	selected := make([]string, 0, len(keys))
+	for _, columnName := range keys {
+		if t.fields[columnName].receiver != nil {
+			selected = append(selected, columnName)
+		}
+	}
+
+	return selected
+}
+
+func (t *Table[T]) tableDialect() Dialect {
+	return t.dialect
+}
+
+// Columns in the table's filters and ordering are written with columnMarker before their names, which is
+// replaced when the table is built. Once sources are joined to the table it becomes the table's name, so
+// that columns shared with a joined source are not ambiguous, as joins may be added after filters.
+const columnMarker = "\x1f"
+
+func (t *Table[T]) columnRef(columnName string) string {
+	return columnMarker + columnName
+}
+
+// Replace the column markers in built with the table's qualifier
+func (t *Table[T]) qualify(built string) string {
+	qualifier := ""
+	if len(t.joins) > 0 {
+		qualifier = t.tableName + "."
+	}
+
+	return strings.ReplaceAll(built, columnMarker, qualifier)
 }
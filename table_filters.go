@@ -3,6 +3,9 @@ package sqb
 import (
 	"fmt"
 	"reflect"
+	"strings"
+
+	"github.com/lib/pq"
 )
 
 // TODO (SSC-3684): There is likely many cases where the default builder filters don't
@@ -25,12 +28,22 @@ func (t *Table[T]) AssertFilterClauseValid(columnName string, param interface{})
 		column = c
 	}
 
-	if reflect.TypeOf(param).Kind() != column.kind && reflect.TypeOf(param) != nullType(column.kind) {
+	paramType := reflect.TypeOf(param)
+	if np, ok := param.(*NamedParam); ok {
+		paramType = np.typ
+		np.column = column
+	}
+
+	if !column.accepts(paramType) {
 		panic(fmt.Sprintf("Incorrect type for column. Need %s, got %s",
-			column.kind,
-			reflect.TypeOf(param),
+			column.typ,
+			paramType,
 		))
 	}
+
+	if !canBind(paramType) {
+		panic(fmt.Sprintf("Cannot bind %s, implement driver.Valuer or use RegisterType", paramType))
+	}
 }
 
 func (t *Table[T]) AssertColumnExists(columnName string) {
@@ -41,8 +54,56 @@ func (t *Table[T]) AssertColumnExists(columnName string) {
 
 func (t *Table[T]) ColumnEquals(columnName string, v interface{}) *Table[T] {
 	t.AssertFilterClauseValid(columnName, v)
+	t.checkEnumValue(columnName, v)
 
-	t.filter.AddClause(NewPrimitiveFilterClause(columnName, "=", "%s", v))
+	t.filter.AddClause(NewPrimitiveFilterClause(t.columnRef(columnName), "=", "%s", v))
+
+	return t
+}
+
+// Filter to rows where the column is equal to any of values
+func (t *Table[T]) ColumnIn(columnName string, values ...interface{}) *Table[T] {
+	if len(values) == 0 {
+		panic(fmt.Sprintf("ColumnIn: no values given for column %s", columnName))
+	}
+
+	placeholders := make([]string, 0, len(values))
+	for _, v := range values {
+		t.AssertFilterClauseValid(columnName, v)
+		t.checkEnumValue(columnName, v)
+
+		placeholders = append(placeholders, "%s")
+	}
+
+	t.filter.AddClause(NewTemplateClause(t.columnRef(columnName)+" IN ("+strings.Join(placeholders, ", ")+")", values...))
+
+	return t
+}
+
+// Values outside of an enum column's allowed values are build errors rather than panics, as they usually
+// come from user input.
+func (t *Table[T]) checkEnumValue(columnName string, v interface{}) {
+	if err := t.fields[columnName].checkEnumValue(v); err != nil {
+		t.errs = append(t.errs, err)
+	}
+}
+
+// Filter to the row with the given primary key. Composite keys take a value per key column, in model field
+// order.
+func (t *Table[T]) GetByID(ids ...interface{}) *Table[T] {
+	pk := t.PrimaryKey()
+
+	if len(pk) == 0 {
+		panic(fmt.Sprintf("GetByID: table %s has no primary key", t.tableName))
+	}
+
+	if len(ids) != len(pk) {
+		panic(fmt.Sprintf("GetByID: table %s has primary key %v, got %d values", t.tableName, pk, len(ids)))
+	}
+
+	for i, columnName := range pk {
+		t.ColumnEquals(columnName, ids[i])
+	}
 
 	return t
 }
@@ -50,17 +111,208 @@ func (t *Table[T]) ColumnEquals(columnName string, v interface{}) *Table[T] {
 func (t *Table[T]) ColumnNull(columnName string) *Table[T] {
 	t.AssertColumnExists(columnName)
 
-	t.filter.AddClause(NewPrimitiveFilterClause(columnName, "IS", "NULL", nil))
+	t.filter.AddClause(NewPrimitiveFilterClause(t.columnRef(columnName), "IS", "NULL", nil))
+
+	return t
+}
+
+// Filter a column by the values produced by a subquery. The subquery must select a single column of the compatible
+// type.
+func (t *Table[T]) ColumnInSubquery(columnName string, subquery Selectable) *Table[T] {
+	t.assertSubqueryValid(columnName, subquery)
+
+	t.filter.AddClause(NewSubqueryClause(t.columnRef(columnName)+" IN (%s)", subquery))
+
+	return t
+}
+
+func (t *Table[T]) ColumnNotInSubquery(columnName string, subquery Selectable) *Table[T] {
+	t.assertSubqueryValid(columnName, subquery)
+
+	t.filter.AddClause(NewSubqueryClause(t.columnRef(columnName)+" NOT IN (%s)", subquery))
+
+	return t
+}
+
+// Filter to rows for which the subquery produces any rows. Use ColumnEqualsOuter on the subquery to
+// correlate it with this table.
+func (t *Table[T]) Exists(subquery Selectable) *Table[T] {
+	t.filter.AddClause(NewSubqueryClause("EXISTS (%s)", subquery))
+
+	return t
+}
+
+func (t *Table[T]) NotExists(subquery Selectable) *Table[T] {
+	t.filter.AddClause(NewSubqueryClause("NOT EXISTS (%s)", subquery))
+
+	return t
+}
+
+// Correlate a column with a column of an enclosing query. Both columns are qualified with their table
+// names, e.g. orders.user_id = users.id
+func (t *Table[T]) ColumnEqualsOuter(columnName string, outer TableRef, outerColumnName string) *Table[T] {
+	column := t.GetColumn(columnName)
+	outerColumn := outer.GetColumn(outerColumnName)
+
+	if !column.accepts(outerColumn.typ) {
+		panic(fmt.Sprintf("Incorrect type for outer column. Need %s, got %s", column.typ, outerColumn.typ))
+	}
+
+	t.filter.AddClause(NewPrimitiveFilterClause(
+		t.tableName+"."+columnName,
+		"=",
+		outer.TableName()+"."+outerColumnName,
+		nil,
+	))
+
+	return t
+}
+
+func (t *Table[T]) assertSubqueryValid(columnName string, subquery Selectable) {
+	t.AssertColumnExists(columnName)
+
+	columns := subquery.ResultColumns()
+	if len(columns) != 1 {
+		panic(fmt.Sprintf("Subquery for column %s must select exactly one column, got %d", columnName, len(columns)))
+	}
+
+	if !t.fields[columnName].accepts(columns[0].typ) {
+		panic(fmt.Sprintf("Incorrect type for subquery column. Need %s, got %s",
+			t.fields[columnName].typ,
+			columns[0].typ,
+		))
+	}
+}
+
+// Filter by the text at path within a json column, e.g. attrs->'size'->>'color' = 'red'. Path elements are
+// object keys. Requires a dialect supporting JSONOperators.
+func (t *Table[T]) JSONPathEquals(columnName string, path []string, v string) *Table[T] {
+	t.AssertJSONColumn(columnName)
+
+	if len(path) == 0 {
+		panic(fmt.Sprintf("JSONPathEquals: empty path for column %s", columnName))
+	}
+
+	template := t.columnRef(columnName)
+	params := make([]any, 0, len(path)+1)
+	for i, key := range path {
+		operator := "->"
+		if i == len(path)-1 {
+			operator = "->>"
+		}
+
+		template += operator + "%s"
+		params = append(params, key)
+	}
+
+	params = append(params, v)
+	t.filter.AddClause(NewFeatureClause(JSONOperators, NewTemplateClause(template+" = %s", params...)))
+
+	return t
+}
+
+// Filter to rows whose jsonb column contains v, encoded as json, e.g. attrs @> '{"color":"red"}'. Requires
+// a dialect supporting JSONOperators.
+func (t *Table[T]) JSONContains(columnName string, v any) *Table[T] {
+	t.assertJSONBColumn("JSONContains", columnName)
+
+	t.filter.AddClause(NewFeatureClause(JSONOperators, NewPrimitiveFilterClause(t.columnRef(columnName), "@>", "%s", jsonParam(v))))
+
+	return t
+}
+
+// Filter to rows whose jsonb column has the top level key. Requires a dialect supporting JSONOperators.
+func (t *Table[T]) JSONHasKey(columnName string, key string) *Table[T] {
+	t.assertJSONBColumn("JSONHasKey", columnName)
+
+	t.filter.AddClause(NewFeatureClause(JSONOperators, NewPrimitiveFilterClause(t.columnRef(columnName), "?", "%s", key)))
+
+	return t
+}
+
+func (t *Table[T]) AssertJSONColumn(columnName string) {
+	column := t.GetColumn(columnName)
+
+	if !isJSONType(column.typ) {
+		panic(fmt.Sprintf("Column %s is not a json column, got %s", columnName, column.typ))
+	}
+}
+
+// @> and ? only exist for jsonb. JSON columns are jsonb unless the type tag option declares otherwise.
+func (t *Table[T]) assertJSONBColumn(filter string, columnName string) {
+	t.AssertJSONColumn(columnName)
+
+	if sqlType := t.GetColumn(columnName).sqlType; sqlType != "" && sqlType != "jsonb" {
+		panic(fmt.Sprintf("%s: column %s is %s, the operator needs jsonb", filter, columnName, sqlType))
+	}
+}
+
+// Filter to rows whose array column contains every element of v, e.g. loves @> '{doom}'. Requires a
+// dialect supporting ArrayOperators.
+func (t *Table[T]) ColumnArrayContains(columnName string, v interface{}) *Table[T] {
+	return t.arrayFilter(columnName, "@>", v)
+}
+
+// Filter to rows whose array column only contains elements of v. Requires a dialect supporting
+// ArrayOperators.
+func (t *Table[T]) ColumnArrayContainedBy(columnName string, v interface{}) *Table[T] {
+	return t.arrayFilter(columnName, "<@", v)
+}
+
+// Filter to rows whose array column shares any element with v. Requires a dialect supporting
+// ArrayOperators.
+func (t *Table[T]) ColumnArrayOverlaps(columnName string, v interface{}) *Table[T] {
+	return t.arrayFilter(columnName, "&&", v)
+}
+
+// Filter to rows whose array column has an element equal to v, e.g. $1 = ANY(loves). Requires a dialect
+// supporting ArrayOperators.
+func (t *Table[T]) ColumnAnyEquals(columnName string, v interface{}) *Table[T] {
+	elemType := t.assertArrayColumn(columnName)
+
+	if !TypesCompatible(elemType, reflect.TypeOf(v)) {
+		panic(fmt.Sprintf("Incorrect type for array element. Need %s, got %s", elemType, reflect.TypeOf(v)))
+	}
+
+	t.filter.AddClause(NewFeatureClause(ArrayOperators, NewTemplateClause("%s = ANY("+t.columnRef(columnName)+")", v)))
 
 	return t
 }
 
+func (t *Table[T]) arrayFilter(columnName string, operator string, v interface{}) *Table[T] {
+	elemType := t.assertArrayColumn(columnName)
+
+	vt := reflect.TypeOf(v)
+	if vt == nil || vt.Kind() != reflect.Slice || !TypesCompatible(elemType, vt.Elem()) {
+		panic(fmt.Sprintf("Incorrect type for array column. Need []%s, got %s", elemType, vt))
+	}
+
+	t.filter.AddClause(NewFeatureClause(ArrayOperators, NewPrimitiveFilterClause(t.columnRef(columnName), operator, "%s", pq.Array(v))))
+
+	return t
+}
+
+// Returns the element type of the array column
+func (t *Table[T]) assertArrayColumn(columnName string) reflect.Type {
+	column := t.GetColumn(columnName)
+
+	if column.typ.Kind() != reflect.Slice || column.typ.Elem().Kind() == reflect.Uint8 {
+		panic(fmt.Sprintf("Column %s is not an array column, got %s", columnName, column.typ))
+	}
+
+	return column.typ.Elem()
+}
+
+// Build the table's filters, including its scopes, for use in other statements. Unscoped tables record an
+// error in params, as when building the table.
 func (t *Table[T]) BuildFilter(params *ParamList) string {
-	return t.filter.Build(params)
+	return t.buildFilters(params)
 }
 
 func (t *Table[T]) AddOrderByClause(columnName string, sortDirection SortDirection) *Table[T] {
-	t.orderBy.AddClause(NewOrderByClause(columnName, sortDirection))
+	t.AssertColumnExists(columnName)
+
+	t.orderBy = append(t.orderBy, NewOrderByClause(t.columnRef(columnName), sortDirection))
 
 	return t
 }
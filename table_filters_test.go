@@ -3,6 +3,7 @@ package sqb_test
 import (
 	"testing"
 
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	sqb "github.com/themanciraptor/SQb"
 )
@@ -52,14 +53,236 @@ func Test_IsNull_BuildsCorrectly(t *testing.T) {
 }
 
 func Test_LimitClause_BuildsCorrectly(t *testing.T) {
-	params := sqb.NewParamList(sqb.Psql())
 	tt := sqb.NewTable[exampleResult]("exampleTable", sqb.Psql(), &exampleModel{}).Limit(25, 5)
 
-	expected := "LIMIT 25 OFFSET 5"
+	expected := "LIMIT $1 OFFSET $2"
 	actual := tt.Build(&exampleResultAccumulator{}, sqb.Psql())
 
-	expectedParams := []interface{}{}
+	expectedParams := []interface{}{int64(25), int64(5)}
 
 	assert.Contains(t, actual.GetQuery(), expected)
-	assert.Equal(t, expectedParams, params.GetParamList())
+	assert.Equal(t, expectedParams, actual.GetParams())
+}
+
+func Test_LimitClause_SharesQueryAcrossPages(t *testing.T) {
+	first := sqb.NewTable[exampleResult]("exampleTable", sqb.Psql(), &exampleModel{}).
+		ColumnEquals("number_of_star", int64(25)).
+		Limit(25, 0).
+		Build(&exampleResultAccumulator{}, sqb.Psql())
+
+	second := sqb.NewTable[exampleResult]("exampleTable", sqb.Psql(), &exampleModel{}).
+		ColumnEquals("number_of_star", int64(25)).
+		Limit(25, 25).
+		Build(&exampleResultAccumulator{}, sqb.Psql())
+
+	assert.Equal(t, first.GetQuery(), second.GetQuery())
+	assert.Contains(t, first.GetQuery(), "WHERE number_of_star = $1 LIMIT $2 OFFSET $3")
+	assert.Equal(t, []interface{}{int64(25), int64(25), int64(0)}, first.GetParams())
+}
+
+func Test_LimitClause_InlinedWhenDialectOptsOut(t *testing.T) {
+	dialect := sqb.InlineLimit(sqb.Psql())
+	tt := sqb.NewTable[exampleResult]("exampleTable", dialect, &exampleModel{}).Limit(25, 5)
+
+	actual := tt.Build(&exampleResultAccumulator{}, dialect)
+
+	assert.Contains(t, actual.GetQuery(), "LIMIT 25 OFFSET 5")
+	assert.Equal(t, []interface{}{}, actual.GetParams())
+}
+
+func Test_LimitClause_BoundWhenDialectDoesNotSay(t *testing.T) {
+	tt := sqb.NewTable[exampleResult]("exampleTable", minimalDialect{}, &exampleModel{}).Limit(25, 5)
+
+	actual := tt.Build(&exampleResultAccumulator{}, minimalDialect{})
+
+	assert.Contains(t, actual.GetQuery(), "LIMIT ? OFFSET ?")
+	assert.Equal(t, []interface{}{int64(25), int64(5)}, actual.GetParams())
+}
+
+type exampleOrderModel struct {
+	Owner  string `psql:"owner"`
+	Status string `psql:"status"`
+	Stars  int64  `psql:"stars"`
+}
+
+func Test_SubqueryFilters_BuildCorrectly(t *testing.T) {
+	type TableFilterBuilder = func(tt *sqb.Table[exampleResult])
+
+	type testCase struct {
+		description    string
+		expectedClause string
+		expectedParams []interface{}
+		TableFilterBuilder
+	}
+
+	orders := func() *sqb.Table[exampleResult] {
+		return sqb.NewTable[exampleResult]("orders", sqb.Psql(), &exampleOrderModel{})
+	}
+
+	testCases := []testCase{
+		{
+			description:    "in subquery shares params with the outer query",
+			expectedClause: "(number_of_star = $1 AND cool IN (SELECT owner FROM orders WHERE status = $2))",
+			expectedParams: []interface{}{int64(3), "open"},
+			TableFilterBuilder: func(tt *sqb.Table[exampleResult]) {
+				tt.ColumnEquals("number_of_star", int64(3)).
+					ColumnInSubquery("cool", orders().Select("owner").ColumnEquals("status", "open"))
+			},
+		},
+		{
+			description:    "not in subquery",
+			expectedClause: "cool NOT IN (SELECT owner FROM orders)",
+			expectedParams: []interface{}{},
+			TableFilterBuilder: func(tt *sqb.Table[exampleResult]) {
+				tt.ColumnNotInSubquery("cool", orders().Select("owner"))
+			},
+		},
+		{
+			description:    "correlated exists",
+			expectedClause: "EXISTS (SELECT 1 FROM orders WHERE (orders.owner = exampleTable.cool AND status = $1))",
+			expectedParams: []interface{}{"open"},
+			TableFilterBuilder: func(tt *sqb.Table[exampleResult]) {
+				tt.Exists(orders().ColumnEqualsOuter("owner", tt, "cool").ColumnEquals("status", "open"))
+			},
+		},
+		{
+			description:    "correlated not exists",
+			expectedClause: "NOT EXISTS (SELECT 1 FROM orders WHERE orders.owner = exampleTable.cool)",
+			expectedParams: []interface{}{},
+			TableFilterBuilder: func(tt *sqb.Table[exampleResult]) {
+				tt.NotExists(orders().ColumnEqualsOuter("owner", tt, "cool"))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			tt := sqb.NewTable[exampleResult]("exampleTable", sqb.Psql(), &exampleModel{})
+			params := sqb.NewParamList(sqb.Psql())
+
+			tc.TableFilterBuilder(tt)
+
+			assert.Equal(t, tc.expectedClause, tt.BuildFilter(params))
+			assert.Equal(t, tc.expectedParams, params.GetParamList())
+		})
+	}
+}
+
+func Test_SubqueryFilters_Panic(t *testing.T) {
+	orders := sqb.NewTable[exampleResult]("orders", sqb.Psql(), &exampleOrderModel{})
+	tt := sqb.NewTable[exampleResult]("exampleTable", sqb.Psql(), &exampleModel{})
+
+	assert.PanicsWithValue(t, "Subquery for column cool must select exactly one column, got 2", func() {
+		tt.ColumnInSubquery("cool", orders.Select("owner", "status"))
+	})
+
+	assert.PanicsWithValue(t, "Incorrect type for subquery column. Need string, got int64", func() {
+		tt.ColumnInSubquery("cool", orders.Select("stars"))
+	})
+}
+
+func Test_OrderByClause_BuildsCorrectly(t *testing.T) {
+	r := exampleResult{}
+	q := sqb.NewTable[exampleResult]("exampleTable", sqb.Psql(), &exampleModel{}).
+		SetColumnReceiver("cool", &r.Name).
+		ColumnEquals("cool", "doom").
+		AddOrderByClause("number_of_star", sqb.Descending).
+		AddOrderByClause("cool", sqb.Ascending).
+		Build(&exampleResultAccumulator{}, sqb.Psql())
+
+	assert.Equal(t, "SELECT cool FROM exampleTable WHERE cool = $1 ORDER BY number_of_star DESC, cool ASC", q.GetQuery())
+	assert.Equal(t, []interface{}{"doom"}, q.GetParams())
+}
+
+func Test_ArrayFilters_BuildCorrectly(t *testing.T) {
+	type TableFilterBuilder = func(tt *sqb.Table[exampleResult])
+
+	type testCase struct {
+		description    string
+		expectedClause string
+		expectedParam  interface{}
+		TableFilterBuilder
+	}
+
+	testCases := []testCase{
+		{
+			description:    "contains",
+			expectedClause: "loves @> $1",
+			expectedParam:  pq.Array([]string{"doom"}),
+			TableFilterBuilder: func(tt *sqb.Table[exampleResult]) {
+				tt.ColumnArrayContains("loves", []string{"doom"})
+			},
+		},
+		{
+			description:    "contained by",
+			expectedClause: "loves <@ $1",
+			expectedParam:  pq.Array([]string{"doom", "gloom"}),
+			TableFilterBuilder: func(tt *sqb.Table[exampleResult]) {
+				tt.ColumnArrayContainedBy("loves", []string{"doom", "gloom"})
+			},
+		},
+		{
+			description:    "overlaps",
+			expectedClause: "loves && $1",
+			expectedParam:  pq.Array([]string{"gloom"}),
+			TableFilterBuilder: func(tt *sqb.Table[exampleResult]) {
+				tt.ColumnArrayOverlaps("loves", []string{"gloom"})
+			},
+		},
+		{
+			description:    "any equals",
+			expectedClause: "$1 = ANY(loves)",
+			expectedParam:  "doom",
+			TableFilterBuilder: func(tt *sqb.Table[exampleResult]) {
+				tt.ColumnAnyEquals("loves", "doom")
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			tt := sqb.NewTable[exampleResult]("exampleTable", sqb.Psql(), &exampleModel{})
+			params := sqb.NewParamList(sqb.Psql())
+
+			tc.TableFilterBuilder(tt)
+
+			assert.Equal(t, tc.expectedClause, tt.BuildFilter(params))
+			assert.Equal(t, []interface{}{tc.expectedParam}, params.GetParamList())
+		})
+	}
+}
+
+// pq has no native array for []int, so binds it as a pq.GenericArray struct wrapping the slice
+func Test_ArrayFilters_BindGenericArrays(t *testing.T) {
+	type exampleScoresModel struct {
+		Nums []int `psql:"nums"`
+	}
+
+	tt := sqb.NewTable[any]("scores", sqb.Psql(), &exampleScoresModel{}).
+		ColumnArrayContains("nums", []int{1}).
+		ColumnArrayOverlaps("nums", []int{2})
+	params := sqb.NewParamList(sqb.Psql())
+
+	assert.Equal(t, "(nums @> $1 AND nums && $2)", tt.BuildFilter(params))
+	assert.Equal(t, []interface{}{pq.Array([]int{1}), pq.Array([]int{2})}, params.GetParamList())
+}
+
+func Test_ArrayFilters_Panic(t *testing.T) {
+	tt := sqb.NewTable[exampleResult]("exampleTable", sqb.Psql(), &exampleModel{})
+
+	assert.PanicsWithValue(t, "Incorrect type for array column. Need []string, got []int64", func() {
+		tt.ColumnArrayContains("loves", []int64{1})
+	})
+
+	assert.PanicsWithValue(t, "Incorrect type for array column. Need []string, got string", func() {
+		tt.ColumnArrayOverlaps("loves", "doom")
+	})
+
+	assert.PanicsWithValue(t, "Incorrect type for array element. Need string, got int64", func() {
+		tt.ColumnAnyEquals("loves", int64(1))
+	})
+
+	assert.PanicsWithValue(t, "Column cool is not an array column, got string", func() {
+		tt.ColumnAnyEquals("cool", "doom")
+	})
 }
@@ -0,0 +1,66 @@
+package sqb
+
+import "strings"
+
+/*
+	Model fields are mapped to columns with a struct tag named by the dialect, e.g. `psql:"status"`. The tag
+	value is the column name, optionally followed by comma separated options:
+
+		pk		the column is part of the primary key
+		readonly	the column is never written, e.g. a generated column
+		omitempty	the column is not written when its value is the zero value
+		default		the database provides a default, so zero values are not written. An SQL
+				expression may be given for DDL, e.g. default=now(). CreateTableSQL needs
+				one unless the column is an integer primary key, which becomes an identity
+		enum=a|b|c	the column only holds the listed values
+		inline		flatten a nested struct's fields into columns, prefixed by the tag's name
+		index		index the column in DDL. Columns sharing a name, index=name, share an index
+		unique		as index, with a unique index
+		type=sql	the column's SQL type in DDL, for Go types the dialect cannot map
+		scope		every select, update and delete must filter by the column, e.g. a tenant id. See
+				Table.Scope
+
+	A tag of "-" skips the field. An empty column name, e.g. `psql:",pk"`, is named by the table's
+	NamingStrategy.
+*/
+
+type tagOptions map[string]string
+
+// Split a tag value into its column name and options. Options without a value are recorded as "".
+func parseTag(tag string) (string, tagOptions) {
+	parts := strings.Split(tag, ",")
+	options := tagOptions{}
+
+	for _, option := range parts[1:] {
+		key, value, _ := strings.Cut(strings.TrimSpace(option), "=")
+		options[key] = value
+	}
+
+	return strings.TrimSpace(parts[0]), options
+}
+
+// Split a tag value into its column name and options, for tools reading table models such as cmd/sqbgen
+func ParseTag(tag string) (string, map[string]string) {
+	return parseTag(tag)
+}
+
+func (s *Column) applyTagOptions(options tagOptions) {
+	_, s.primaryKey = options["pk"]
+	_, s.readonly = options["readonly"]
+	_, s.omitEmpty = options["omitempty"]
+	_, s.scope = options["scope"]
+	s.defaultValue, s.hasDefault = options["default"]
+
+	s.sqlType = options["type"]
+
+	for _, option := range []string{"index", "unique"} {
+		if name, ok := options[option]; ok {
+			s.sqlIndexes = append(s.sqlIndexes, sqlIndex{name: name, unique: option == "unique"})
+		}
+	}
+
+	s.enum = lookupEnum(s.typ)
+	if values, ok := options["enum"]; ok {
+		s.enum = strings.Split(values, "|")
+	}
+}
@@ -0,0 +1,194 @@
+package sqb
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+/*
+	Templates let a query shape be built once and reused. Filters are given named params in place of values,
+	and the values are bound each time the query is run. Binding only swaps the params, so no reflection over
+	the model or SQL building happens per query.
+*/
+
+// A placeholder for a filter value which is bound later through a Template
+type NamedParam struct {
+	name string
+	typ  reflect.Type
+
+	// The column the param filters, recorded when the filter is added to a table
+	column *Column
+}
+
+// Declare a named param to be bound through a Template. The param type is checked against the column it
+// filters when the filter is added.
+func Param[T any](name string) *NamedParam {
+	return &NamedParam{
+		name: name,
+		typ:  reflect.TypeOf((*T)(nil)).Elem(),
+	}
+}
+
+func (p *NamedParam) Name() string {
+	return p.name
+}
+
+type Template[T any] struct {
+	query   *Query[T]
+	dialect Dialect
+
+	// Columns in scan order
+	columns []string
+	// Validates receivers of accumulators bound with BindTo
+	table *Table[T]
+}
+
+// Build the table once so it can be bound many times. The table should not be modified afterwards.
+func (t *Table[T]) Compile(dialect Dialect) *Template[T] {
+	q, err := t.tryBuild(t.accumulator, dialect, true)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return &Template[T]{
+		query:   q,
+		dialect: dialect,
+		columns: t.selectedColumns(),
+		table:   t,
+	}
+}
+
+func (t *Template[T]) GetQuery() string {
+	return t.query.query
+}
+
+// Bind values to the template's params, returning a query which accumulates into the accumulator loaded
+// onto the table. values must be a map[string]any keyed by param name, or a struct whose fields are tagged
+// with, or named after, the param names.
+//
+// Queries bound this way share receivers, use BindTo when queries may run concurrently.
+func (t *Template[T]) Bind(values any) (*Query[T], error) {
+	if t.query.accumulator == nil {
+		return nil, errors.New("Template: no accumulator loaded onto the table, use BindTo")
+	}
+
+	params, err := t.bindParams(values)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Query[T]{
+		query:       t.query.query,
+		scanList:    t.query.scanList,
+		params:      params,
+		dialect:     t.dialect,
+		table:       t.query.table,
+		statement:   t.query.statement,
+		accumulator: t.query.accumulator,
+	}, nil
+}
+
+// Bind values to the template's params, returning a query which accumulates into a. a must provide a
+// receiver for every column the template selects.
+func (t *Template[T]) BindTo(a Accumulator[T], values any) (*Query[T], error) {
+	receivers := a.GetColumnReceiverMap()
+
+	columnErrors := t.table.checkReceivers(receivers)
+	scanList := make([]interface{}, 0, len(t.columns))
+	for _, columnName := range t.columns {
+		receiver, ok := receivers[columnName]
+		if !ok {
+			columnErrors[columnName] = "no receiver for selected column"
+			continue
+		}
+
+		scanList = append(scanList, t.table.fields[columnName].scanTarget(receiver))
+	}
+
+	if len(columnErrors) > 0 {
+		return nil, fmt.Errorf("Template: invalid accumulator:\n%s", formatColumnErrors(columnErrors))
+	}
+
+	params, err := t.bindParams(values)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Query[T]{
+		query:       t.query.query,
+		scanList:    scanList,
+		params:      params,
+		dialect:     t.dialect,
+		table:       t.query.table,
+		statement:   t.query.statement,
+		accumulator: a,
+	}, nil
+}
+
+func (t *Template[T]) bindParams(values any) ([]interface{}, error) {
+	lookup, err := t.paramLookup(values)
+	if err != nil {
+		return nil, err
+	}
+
+	params := make([]interface{}, len(t.query.params))
+	for i, p := range t.query.params {
+		np, ok := p.(*NamedParam)
+		if !ok {
+			params[i] = p
+			continue
+		}
+
+		v, ok := lookup(np.name)
+		if !ok {
+			return nil, fmt.Errorf("Template: no value bound for param %s", np.name)
+		}
+
+		vt := reflect.TypeOf(v)
+		if !TypesCompatible(np.typ, vt) || (np.column != nil && !np.column.accepts(vt)) {
+			return nil, fmt.Errorf("Template: incorrect type for param %s. Need %s, got %v", np.name, np.typ, vt)
+		}
+
+		if np.column != nil {
+			if err := np.column.checkEnumValue(v); err != nil {
+				return nil, fmt.Errorf("Template: %w", err)
+			}
+		}
+
+		params[i] = bindValue(v)
+	}
+
+	return params, nil
+}
+
+func (t *Template[T]) paramLookup(values any) (func(name string) (any, bool), error) {
+	if m, ok := values.(map[string]any); ok {
+		return func(name string) (any, bool) {
+			v, ok := m[name]
+			return v, ok
+		}, nil
+	}
+
+	v := reflect.Indirect(reflect.ValueOf(values))
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("Template: values must be a map[string]any or struct, got %T", values)
+	}
+
+	return func(name string) (any, bool) {
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+
+			// Fields are matched by their column name, as models are, ignoring tag options
+			if columnName, _ := parseTag(field.Tag.Get(t.dialect.StructTag())); columnName == name || strings.EqualFold(field.Name, name) {
+				return v.Field(i).Interface(), true
+			}
+		}
+
+		return nil, false
+	}, nil
+}
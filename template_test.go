@@ -0,0 +1,158 @@
+package sqb_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	sqb "github.com/themanciraptor/SQb"
+	"github.com/themanciraptor/SQb/internal/fakedb"
+)
+
+func compileStarTemplate() *sqb.Template[exampleResult] {
+	acc := sqb.NewAccumulator(func(r *exampleResult) map[string]interface{} {
+		return map[string]interface{}{
+			"cool": &r.Name,
+		}
+	})
+
+	return sqb.NewTable[exampleResult]("exampleTable", sqb.Psql(), &exampleModel{}).
+		LoadReceiversFromAccumulator(acc).
+		ColumnEquals("number_of_star", sqb.Param[int64]("stars")).
+		ColumnEquals("cool", "doom").
+		Limit(10, 0).
+		Compile(sqb.Psql())
+}
+
+func Test_Template_BindsParams(t *testing.T) {
+	tmpl := compileStarTemplate()
+
+	type testCase struct {
+		description string
+		values      any
+	}
+
+	testCases := []testCase{
+		{
+			description: "from a map",
+			values:      map[string]any{"stars": int64(5)},
+		},
+		{
+			description: "from a struct field named after the param",
+			values:      struct{ Stars int64 }{Stars: 5},
+		},
+		{
+			description: "from a tagged struct field",
+			values: struct {
+				Count int64 `psql:"stars"`
+			}{Count: 5},
+		},
+		{
+			description: "from a struct field tagged with options",
+			values: struct {
+				Count int64 `psql:"stars,omitempty"`
+			}{Count: 5},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			q, err := tmpl.Bind(tc.values)
+
+			assert.NoError(t, err)
+			assert.Equal(t, "SELECT cool FROM exampleTable WHERE (number_of_star = $1 AND cool = $2) LIMIT $3 OFFSET $4", q.GetQuery())
+			assert.Equal(t, []interface{}{int64(5), "doom", int64(10), int64(0)}, q.GetParams())
+		})
+	}
+}
+
+func Test_Template_BindErrors(t *testing.T) {
+	tmpl := compileStarTemplate()
+
+	type testCase struct {
+		description string
+		values      any
+		errMsg      string
+	}
+
+	testCases := []testCase{
+		{
+			description: "when a param has no value",
+			values:      map[string]any{"start": int64(5)},
+			errMsg:      "no value bound for param stars",
+		},
+		{
+			description: "when a value does not match the column kind",
+			values:      map[string]any{"stars": "five"},
+			errMsg:      "incorrect type for param stars",
+		},
+		{
+			description: "when a value is nil",
+			values:      map[string]any{"stars": nil},
+			errMsg:      "incorrect type for param stars. Need int64, got <nil>",
+		},
+		{
+			description: "when values are not a map or struct",
+			values:      5,
+			errMsg:      "values must be a map[string]any or struct",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			_, err := tmpl.Bind(tc.values)
+
+			assert.ErrorContains(t, err, tc.errMsg)
+		})
+	}
+}
+
+func Test_Param_MustBeCompiled(t *testing.T) {
+	r := exampleResult{}
+	tt := sqb.NewTable[exampleResult]("exampleTable", sqb.Psql(), &exampleModel{}).
+		SetColumnReceiver("cool", &r.Name).
+		ColumnEquals("number_of_star", sqb.Param[int64]("stars"))
+
+	_, err := tt.TryBuild(&exampleResultAccumulator{}, sqb.Psql())
+	assert.EqualError(t, err, "Build: param stars must be bound through a Template, use Compile")
+
+	_, err = sqb.NewTable[any]("users", sqb.Psql(), &exampleUserModel{}).
+		ColumnEquals("id", sqb.Param[int64]("id")).
+		Delete().
+		TryBuild(sqb.Psql())
+	assert.EqualError(t, err, "Build: param id must be bound through a Template, use Compile")
+}
+
+func Test_Param_PanicsWhenTypeDoesNotMatchColumn(t *testing.T) {
+	assert.PanicsWithValue(t, "Incorrect type for column. Need int64, got string", func() {
+		sqb.NewTable[exampleResult]("exampleTable", sqb.Psql(), &exampleModel{}).
+			ColumnEquals("number_of_star", sqb.Param[string]("stars"))
+	})
+}
+
+func Test_Template_BindToUsesSeparateReceivers(t *testing.T) {
+	db, _ := fakedb.New(nameHandler)
+	tmpl := compileStarTemplate()
+
+	newAcc := func() sqb.Accumulator[exampleResult] {
+		return sqb.NewAccumulator(func(r *exampleResult) map[string]interface{} {
+			return map[string]interface{}{
+				"cool": &r.Name,
+			}
+		})
+	}
+
+	first, second := newAcc(), newAcc()
+
+	q, err := tmpl.BindTo(first, map[string]any{"stars": int64(1)})
+	assert.NoError(t, err)
+	assert.NoError(t, q.Run(context.Background(), sqb.NewPreparedRunner(db, 1)))
+
+	assert.Len(t, first.GetResults(), 2)
+	assert.Empty(t, second.GetResults())
+
+	_, err = tmpl.BindTo(sqb.NewAccumulator(func(r *exampleResult) map[string]interface{} {
+		return map[string]interface{}{}
+	}), map[string]any{"stars": int64(1)})
+	assert.ErrorContains(t, err, "cool: no receiver for selected column")
+}
@@ -7,31 +7,103 @@ import (
 	"time"
 )
 
-// Used to allow nullable fields in scanPair receivers
-func nullType(k reflect.Kind) reflect.Type {
-	switch k {
-	case reflect.String:
-		return reflect.TypeOf(NullString{})
-	case reflect.Int64:
-		return reflect.TypeOf(NullInt64{})
-	case reflect.Int32:
-		return reflect.TypeOf(NullInt32{})
-	case reflect.Float64:
-		return reflect.TypeOf(NullFloat64{})
-	case reflect.Bool:
-		return reflect.TypeOf(NullBool{})
-	case reflect.Struct:
-		return reflect.TypeOf(NullTime{})
-	}
-
-	return reflect.TypeOf(nil)
+// Nullable wrappers report the type of the value they wrap, so that they can be type checked against
+// columns in place of that value.
+type nullable interface {
+	nullValueType() reflect.Type
+}
+
+var nullableType = reflect.TypeOf((*nullable)(nil)).Elem()
+
+// If t is a nullable wrapper, returns the type of the value it wraps
+func nullValueType(t reflect.Type) (reflect.Type, bool) {
+	if t.Kind() == reflect.Ptr || !t.Implements(nullableType) {
+		return nil, false
+	}
+
+	return reflect.Zero(t).Interface().(nullable).nullValueType(), true
+}
+
+// Null wraps sql.Null to scan nullable columns directly into a receiver of any scannable type. On NULL the
+// receiver is set to its default value, the zero value unless WithDefault is used, so a shared receiver
+// never keeps the value of a previous row. Receivers of pointer types, e.g. **int64, are set to nil on NULL.
+//
+// Call NewNull() to scan into a receiver. A Null used as a model or result field, without a receiver,
+// holds the scanned value itself, see Get. Its zero value is NULL, and NullOf makes a non-NULL one to write.
+type Null[T any] struct {
+	receiver     *T
+	defaultValue T
+	ns           sql.Null[T]
+	Valid        bool
+}
+
+func (n *Null[T]) Scan(value interface{}) error {
+	err := n.ns.Scan(value)
+	if err != nil {
+		return err
+	}
+
+	n.Valid = n.ns.Valid
+	if n.receiver == nil {
+		return nil
+	}
+
+	if n.Valid {
+		*n.receiver = n.ns.V
+	} else {
+		*n.receiver = n.defaultValue
+	}
+
+	return nil
+}
+
+func (n Null[T]) Value() (driver.Value, error) {
+	return n.ns.Value()
+}
+
+// The scanned value, or the default value if the column was NULL
+func (n Null[T]) Get() T {
+	if !n.ns.Valid {
+		return n.defaultValue
+	}
+
+	return n.ns.V
+}
+
+// Set the value written to the receiver when the column is NULL
+func (n *Null[T]) WithDefault(v T) *Null[T] {
+	n.defaultValue = v
+	return n
+}
+
+func (n Null[T]) nullValueType() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+func NewNull[T any](receiver *T) *Null[T] {
+	return &Null[T]{
+		receiver: receiver,
+		Valid:    false,
+	}
+}
+
+// A Null holding v, e.g. to set a nullable field of a model being inserted
+func NullOf[T any](v T) Null[T] {
+	return Null[T]{
+		ns:    sql.Null[T]{V: v, Valid: true},
+		Valid: true,
+	}
 }
 
 /*
 	Wrapper around the sql NullTypes to allow us to scan directly to a receiver.
 	We can still check the valid boolean.
+
+	These predate Null, which supports any scannable type and should be preferred.
 */
 
+// Deprecated: use NewNull.
+//
 // Should not be instantiated directly, call NewNullString()
 type NullString struct {
 	String *string
@@ -55,6 +127,11 @@ func (n NullString) Value() (driver.Value, error) {
 	return n.ns.Value()
 }
 
+func (n NullString) nullValueType() reflect.Type {
+	return reflect.TypeOf("")
+}
+
+// Deprecated: use NewNull.
 func NewNullString(receiver *string) *NullString {
 	ns := &NullString{
 		ns:     &sql.NullString{},
@@ -65,6 +142,8 @@ func NewNullString(receiver *string) *NullString {
 	return ns
 }
 
+// Deprecated: use NewNull.
+//
 // Should not be instantiated directly, call NewNullInt64()
 type NullInt64 struct {
 	Int64 *int64
@@ -88,6 +167,11 @@ func (n NullInt64) Value() (driver.Value, error) {
 	return n.ns.Value()
 }
 
+func (n NullInt64) nullValueType() reflect.Type {
+	return reflect.TypeOf(int64(0))
+}
+
+// Deprecated: use NewNull.
 func NewNullInt64(receiver *int64) *NullInt64 {
 	ns := &NullInt64{
 		ns:    &sql.NullInt64{},
@@ -98,6 +182,8 @@ func NewNullInt64(receiver *int64) *NullInt64 {
 	return ns
 }
 
+// Deprecated: use NewNull.
+//
 // Should not be instantiated directly, call NewNullInt32()
 type NullInt32 struct {
 	Int32 *int32
@@ -121,6 +207,11 @@ func (n NullInt32) Value() (driver.Value, error) {
 	return n.ns.Value()
 }
 
+func (n NullInt32) nullValueType() reflect.Type {
+	return reflect.TypeOf(int32(0))
+}
+
+// Deprecated: use NewNull.
 func NewNullInt32(receiver *int32) *NullInt32 {
 	ns := &NullInt32{
 		ns:    &sql.NullInt32{},
@@ -131,6 +222,8 @@ func NewNullInt32(receiver *int32) *NullInt32 {
 	return ns
 }
 
+// Deprecated: use NewNull.
+//
 // Should not be instantiated directly, call NewNullFloat64()
 type NullFloat64 struct {
 	Float64 *float64
@@ -154,6 +247,11 @@ func (n NullFloat64) Value() (driver.Value, error) {
 	return n.ns.Value()
 }
 
+func (n NullFloat64) nullValueType() reflect.Type {
+	return reflect.TypeOf(float64(0))
+}
+
+// Deprecated: use NewNull.
 func NewNullFloat64(receiver *float64) *NullFloat64 {
 	ns := &NullFloat64{
 		ns:      &sql.NullFloat64{},
@@ -164,6 +262,8 @@ func NewNullFloat64(receiver *float64) *NullFloat64 {
 	return ns
 }
 
+// Deprecated: use NewNull.
+//
 // Should not be instantiated directly, call NewNullBool()
 type NullBool struct {
 	Bool  *bool
@@ -187,6 +287,11 @@ func (n NullBool) Value() (driver.Value, error) {
 	return n.ns.Value()
 }
 
+func (n NullBool) nullValueType() reflect.Type {
+	return reflect.TypeOf(false)
+}
+
+// Deprecated: use NewNull.
 func NewNullBool(receiver *bool) *NullBool {
 	ns := &NullBool{
 		ns:    &sql.NullBool{},
@@ -197,6 +302,8 @@ func NewNullBool(receiver *bool) *NullBool {
 	return ns
 }
 
+// Deprecated: use NewNull.
+//
 // Should not be instantiated directly, call NewNullTime()
 type NullTime struct {
 	Time  *time.Time
@@ -220,6 +327,11 @@ func (n NullTime) Value() (driver.Value, error) {
 	return n.ns.Value()
 }
 
+func (n NullTime) nullValueType() reflect.Type {
+	return reflect.TypeOf(time.Time{})
+}
+
+// Deprecated: use NewNull.
 func NewNullTime(receiver *time.Time) *NullTime {
 	ns := &NullTime{
 		ns:    &sql.NullTime{},
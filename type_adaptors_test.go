@@ -0,0 +1,93 @@
+package sqb_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	sqb "github.com/themanciraptor/SQb"
+)
+
+func Test_Null_ScansValuesAndNulls(t *testing.T) {
+	var receiver int16 = 7
+	n := sqb.NewNull(&receiver)
+
+	assert.NoError(t, n.Scan(int64(42)))
+	assert.Equal(t, int16(42), receiver)
+	assert.True(t, n.Valid)
+
+	assert.NoError(t, n.Scan(nil))
+	assert.Equal(t, int16(0), receiver)
+	assert.False(t, n.Valid)
+}
+
+func Test_Null_HoldsValueWithoutReceiver(t *testing.T) {
+	var n sqb.Null[string]
+
+	assert.NoError(t, n.Scan("doom"))
+	assert.Equal(t, "doom", n.Get())
+	assert.True(t, n.Valid)
+
+	assert.NoError(t, n.Scan(nil))
+	assert.Equal(t, "", n.Get())
+	assert.False(t, n.Valid)
+}
+
+func Test_NullOf_WritesValue(t *testing.T) {
+	n := sqb.NullOf("doom")
+	assert.True(t, n.Valid)
+	assert.Equal(t, "doom", n.Get())
+
+	v, err := n.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "doom", v)
+
+	v, err = sqb.Null[string]{}.Value()
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+
+	q := sqb.NewTable[any]("accounts", sqb.Psql(), &exampleAccountModel{}).
+		Insert(exampleAccountModel{Email: "a@b.c", Nickname: sqb.NullOf("doom")}).
+		Build(sqb.Psql())
+
+	assert.Contains(t, q.GetParams(), sqb.NullOf("doom"))
+}
+
+func Test_Null_WritesDefaultOnNull(t *testing.T) {
+	receiver := "previous row"
+	n := sqb.NewNull(&receiver).WithDefault("unknown")
+
+	assert.NoError(t, n.Scan(nil))
+	assert.Equal(t, "unknown", receiver)
+}
+
+func Test_Null_SupportsPointerReceivers(t *testing.T) {
+	var receiver *time.Time
+	n := sqb.NewNull(&receiver)
+
+	expected := time.Date(2011, 11, 11, 0, 0, 0, 0, time.UTC)
+	assert.NoError(t, n.Scan(expected))
+	assert.Equal(t, expected, *receiver)
+
+	assert.NoError(t, n.Scan(nil))
+	assert.Nil(t, receiver)
+}
+
+func Test_Null_IsTypeCheckedAgainstWrappedType(t *testing.T) {
+	tt := sqb.NewTable[any]("exampleTable", sqb.Psql(), &exampleModel{})
+	r := exampleResult{}
+	var stars *int64
+
+	assert.NotPanics(t, func() {
+		tt.SetColumnReceiver("number_of_food", sqb.NewNull(&r.NumFoods)).
+			SetColumnReceiver("number_of_star", sqb.NewNull(&stars)).
+			SetColumnReceiver("created_time", sqb.NewNull(&r.Created))
+	})
+
+	assert.Panics(t, func() {
+		tt.SetColumnReceiver("cool", sqb.NewNull(&r.Created))
+	})
+
+	assert.True(t, sqb.TypesCompatible(reflect.TypeOf(int64(0)), reflect.TypeOf(sqb.Null[int16]{})))
+}
@@ -0,0 +1,323 @@
+package sqb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+/*
+	Verify compares table models with the database, so drift between them is found at startup or in tests
+	rather than by a failing query. Each model column is checked against the database's description of it:
+
+		missing		the column does not exist in the database
+		type		the column's SQL type cannot be scanned into the model's Go type, or its integers
+				would overflow it
+		nullability	the column is nullable, but its receiver cannot hold NULL
+
+	Columns whose Go type scans itself, e.g. a sql.Scanner or a type added with RegisterType, are not type
+	checked, as only the type knows what it accepts. Columns with a receiver set are checked for nullability
+	using the receiver, otherwise the model's field type is used. Only pointers, slices, maps, JSON, Null and
+	sql.Null style wrappers with a Valid field hold NULL.
+
+	Table names are resolved by the database, so unqualified names are found through its search path and
+	qualified names, e.g. billing.invoices, in their schema.
+*/
+
+type MismatchKind int
+
+const (
+	MissingColumn MismatchKind = iota
+	TypeMismatch
+	NullabilityMismatch
+)
+
+func (k MismatchKind) String() string {
+	switch k {
+	case MissingColumn:
+		return "missing"
+	case TypeMismatch:
+		return "type"
+	case NullabilityMismatch:
+		return "nullability"
+	default:
+		return fmt.Sprintf("mismatch %d", int(k))
+	}
+}
+
+type SchemaMismatch struct {
+	Table  string
+	Column string
+	Kind   MismatchKind
+	Detail string
+}
+
+func (m SchemaMismatch) String() string {
+	return fmt.Sprintf("%s.%s: %s: %s", m.Table, m.Column, m.Kind, m.Detail)
+}
+
+// Returned by Verify when the models and the database differ
+type SchemaDriftError struct {
+	Mismatches []SchemaMismatch
+}
+
+func (e *SchemaDriftError) Error() string {
+	lines := make([]string, 0, len(e.Mismatches))
+	for _, m := range e.Mismatches {
+		lines = append(lines, m.String())
+	}
+
+	return fmt.Sprintf("Verify: %d schema mismatches:\n%s", len(e.Mismatches), strings.Join(lines, "\n"))
+}
+
+// Go types which each SQL type scans into, keyed by data type and by the element type names of arrays
+var sqlTypes = map[string][]reflect.Type{}
+
+func init() {
+	register := func(t reflect.Type, names ...string) {
+		for _, name := range names {
+			sqlTypes[name] = append(sqlTypes[name], t)
+		}
+	}
+
+	register(reflect.TypeOf(int64(0)), "smallint", "integer", "bigint", "int2", "int4", "int8")
+	register(reflect.TypeOf(uint64(0)), "smallint", "integer", "bigint", "int2", "int4", "int8", "numeric")
+	register(reflect.TypeOf(float64(0)), "real", "double precision", "numeric", "float4", "float8")
+	register(reflect.TypeOf(""), "numeric", "text", "character varying", "character", "uuid", "citext", "USER-DEFINED", "varchar", "bpchar")
+	register(reflect.TypeOf(true), "boolean", "bool")
+	register(timeType, "timestamp without time zone", "timestamp with time zone", "date", "timestamp", "timestamptz")
+	register(reflect.TypeOf([]byte{}), "bytea")
+	register(reflect.TypeOf(json.RawMessage{}), "json", "jsonb")
+}
+
+type databaseColumn struct {
+	dataType string
+	udtName  string
+	nullable bool
+}
+
+// Check the columns of tables against the database, returning a *SchemaDriftError listing any mismatches.
+// The tables must share a dialect, given when they were created, which implements ColumnsQuerier.
+func Verify(ctx context.Context, runner tempDriver, tables ...TableRef) error {
+	dialect, err := tablesDialect(tables)
+	if err != nil {
+		return err
+	}
+
+	querier, ok := DialectAs[ColumnsQuerier](dialect)
+	if !ok {
+		return fmt.Errorf("Verify: dialect %T cannot describe columns, implement ColumnsQuerier", dialect)
+	}
+
+	tableNames := make([]string, 0, len(tables))
+	for _, table := range tables {
+		tableNames = append(tableNames, table.TableName())
+	}
+
+	params := NewParamList(dialect)
+	query := querier.ColumnsQuery(params, tableNames)
+
+	res, closer, err := runner.RunQuery(ctx, query, params.GetParamList())
+	if err != nil {
+		return errors.Join(err, errors.New("Verify: failed to query columns"))
+	}
+	defer closer(ctx)
+
+	described := map[string]map[string]databaseColumn{}
+
+	for res.Next() {
+		var tableName, columnName, isNullable string
+		var column databaseColumn
+
+		if err := res.Scan(&tableName, &columnName, &column.dataType, &column.udtName, &isNullable); err != nil {
+			return errors.Join(err, errors.New("Verify: failed to scan column"))
+		}
+
+		column.nullable = isNullable == "YES"
+
+		if described[tableName] == nil {
+			described[tableName] = map[string]databaseColumn{}
+		}
+
+		described[tableName][columnName] = column
+	}
+
+	var mismatches []SchemaMismatch
+
+	for _, table := range tables {
+		for _, column := range table.Columns() {
+			mismatch := SchemaMismatch{Table: table.TableName(), Column: column.name}
+
+			dbColumn, ok := described[table.TableName()][column.name]
+			if !ok {
+				mismatch.Kind = MissingColumn
+				mismatch.Detail = "column does not exist"
+				mismatches = append(mismatches, mismatch)
+				continue
+			}
+
+			if detail, ok := column.checkSQLType(dbColumn); !ok {
+				mismatch.Kind = TypeMismatch
+				mismatch.Detail = detail
+				mismatches = append(mismatches, mismatch)
+			}
+
+			if dbColumn.nullable && !column.holdsNull() {
+				mismatch.Kind = NullabilityMismatch
+				mismatch.Detail = fmt.Sprintf("column is nullable, %s cannot hold NULL", column.nullCheckedType())
+				mismatches = append(mismatches, mismatch)
+			}
+		}
+	}
+
+	if len(mismatches) > 0 {
+		return &SchemaDriftError{Mismatches: mismatches}
+	}
+
+	return nil
+}
+
+// The dialect of the tables, taken from the first. Every table must have one.
+func tablesDialect(tables []TableRef) (Dialect, error) {
+	if len(tables) == 0 {
+		return nil, errors.New("Verify: no tables given")
+	}
+
+	for _, table := range tables {
+		withDialect, ok := table.(interface{ tableDialect() Dialect })
+		if !ok || withDialect.tableDialect() == nil {
+			return nil, fmt.Errorf("Verify: table %s has no dialect", table.TableName())
+		}
+	}
+
+	return tables[0].(interface{ tableDialect() Dialect }).tableDialect(), nil
+}
+
+// Whether the column's Go type can hold values of the database column's SQL type. Unknown SQL types are
+// not checked.
+func (s *Column) checkSQLType(dbColumn databaseColumn) (string, bool) {
+	goType := unwrapNullable(s.typ)
+	if goType != timeType && !isJSONType(goType) {
+		if _, ok := lookupRegisteredType(goType); ok || reflect.PointerTo(goType).Implements(scannerType) {
+			return "", true
+		}
+	}
+
+	sqlType := dbColumn.dataType
+	candidates := sqlTypes[sqlType]
+
+	if sqlType == "ARRAY" {
+		sqlType = dbColumn.udtName
+		for _, element := range sqlTypes[strings.TrimPrefix(sqlType, "_")] {
+			candidates = append(candidates, reflect.SliceOf(element))
+		}
+	}
+
+	if len(candidates) == 0 {
+		return "", true
+	}
+
+	detail := fmt.Sprintf("column type %s, model type %s", sqlType, s.typ)
+
+	for _, candidate := range candidates {
+		if !s.accepts(candidate) {
+			continue
+		}
+
+		if narrowsInteger(strings.TrimPrefix(sqlType, "_"), goType) {
+			return detail + " is too small", false
+		}
+
+		return "", true
+	}
+
+	return detail, false
+}
+
+// The width of each SQL integer type
+var sqlIntegerBits = map[string]int{
+	"smallint": 16, "int2": 16,
+	"integer": 32, "int4": 32,
+	"bigint": 64, "int8": 64,
+}
+
+// Whether values of the SQL integer type, or of its elements for arrays, could overflow goType. The SQL type
+// is compared with the narrowest one holding every value of goType, which CreateTableSQL writes for it, e.g.
+// smallint for int8 and uint8, and bigint for uint32.
+func narrowsInteger(sqlType string, goType reflect.Type) bool {
+	if goType.Kind() == reflect.Slice {
+		goType = unwrapNullable(goType.Elem())
+	}
+
+	bits, ok := sqlIntegerBits[sqlType]
+	if !ok {
+		return false
+	}
+
+	var needed int
+	switch {
+	case isSignedInt(goType.Kind()):
+		needed = goType.Bits()
+	case isUnsignedInt(goType.Kind()):
+		// SQL integers are signed, so unsigned values need a type twice as wide
+		needed = 2 * goType.Bits()
+	default:
+		return false
+	}
+
+	return max(needed, 16) < bits
+}
+
+// The type checked for nullability: the type the receiver scans into if one is set, otherwise the model's
+// field type
+func (s *Column) nullCheckedType() reflect.Type {
+	if s.receiver == nil {
+		return s.typ
+	}
+
+	dest := s.receiver
+	for {
+		switch wrapper := dest.(type) {
+		case *enumScanner:
+			dest = wrapper.dest
+		case *registeredScanner:
+			dest = wrapper.dest
+		case pq.GenericArray:
+			dest = wrapper.A
+		default:
+			return reflect.TypeOf(dest).Elem()
+		}
+	}
+}
+
+func (s *Column) holdsNull() bool {
+	return typeHoldsNull(s.nullCheckedType())
+}
+
+// Whether NULL can be scanned into a value of type t. Other scanners, e.g. enums and registered types, are
+// assumed to reject NULL.
+func typeHoldsNull(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface:
+		return true
+	}
+
+	// JSON scans NULL as its zero value
+	if _, ok := nullValueType(t); ok || isJSONType(t) {
+		return true
+	}
+
+	// The database/sql convention, e.g. sql.NullString and sql.Null[T]
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+
+	valid, ok := t.FieldByName("Valid")
+
+	return ok && valid.Type.Kind() == reflect.Bool && reflect.PointerTo(t).Implements(scannerType)
+}
@@ -0,0 +1,176 @@
+package sqb_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	sqb "github.com/themanciraptor/SQb"
+	"github.com/themanciraptor/SQb/internal/fakedb"
+)
+
+func Test_Verify_ReportsMismatches(t *testing.T) {
+	var executed string
+	var executedArgs []driver.Value
+
+	db, _ := fakedb.New(func(query string, args []driver.Value) ([]string, [][]driver.Value, error) {
+		executed, executedArgs = query, args
+
+		return []string{"table_name", "column_name", "data_type", "udt_name", "is_nullable"}, [][]driver.Value{
+			{"users", "id", "integer", "int4", "NO"},
+			{"users", "email", "text", "text", "YES"},
+			{"users", "created_at", "text", "text", "NO"},
+			{"exampleTable", "cool", "character varying", "varchar", "YES"},
+			{"exampleTable", "created_time", "timestamp with time zone", "timestamptz", "NO"},
+			{"exampleTable", "number_of_food", "bigint", "int8", "NO"},
+			{"exampleTable", "number_of_star", "bigint", "int8", "NO"},
+			{"exampleTable", "radius_of_moon", "numeric", "numeric", "NO"},
+			{"exampleTable", "is_true_true", "boolean", "bool", "NO"},
+			{"exampleTable", "loves", "ARRAY", "_int4", "YES"},
+			{"tickets", "id", "bigint", "int8", "NO"},
+			{"tickets", "status", "USER-DEFINED", "ticket_status", "YES"},
+			{"tickets", "priority", "text", "text", "YES"},
+		}, nil
+	})
+
+	var name, status string
+	dialect := sqb.InlineLimit(sqb.Psql())
+	users := sqb.NewTable[any]("users", dialect, &exampleUserModel{})
+	examples := sqb.NewTable[any]("exampleTable", dialect, &exampleModel{}).SetColumnReceiver("cool", sqb.NewNull(&name))
+	tickets := sqb.NewTable[any]("tickets", dialect, &exampleTicketModel{}).SetColumnReceiver("status", &status)
+
+	err := sqb.Verify(context.Background(), sqb.NewPreparedRunner(db, 1), users, examples, tickets)
+
+	assert.Equal(t, "SELECT t.name, c.column_name, c.data_type, c.udt_name, c.is_nullable FROM (VALUES ($1::text), ($2::text), ($3::text)) AS t(name) "+
+		"JOIN pg_class r ON r.oid = to_regclass(t.name) JOIN pg_namespace n ON n.oid = r.relnamespace "+
+		"JOIN information_schema.columns c ON c.table_schema = n.nspname AND c.table_name = r.relname "+
+		"ORDER BY t.name, c.ordinal_position", executed)
+	assert.Equal(t, []driver.Value{"users", "exampleTable", "tickets"}, executedArgs)
+
+	var drift *sqb.SchemaDriftError
+	assert.ErrorAs(t, err, &drift)
+	assert.Equal(t, []sqb.SchemaMismatch{
+		{Table: "users", Column: "email", Kind: sqb.NullabilityMismatch, Detail: "column is nullable, string cannot hold NULL"},
+		{Table: "users", Column: "nickname", Kind: sqb.MissingColumn, Detail: "column does not exist"},
+		{Table: "users", Column: "created_at", Kind: sqb.TypeMismatch, Detail: "column type text, model type time.Time"},
+		{Table: "exampleTable", Column: "number_of_food", Kind: sqb.TypeMismatch, Detail: "column type bigint, model type int32 is too small"},
+		{Table: "exampleTable", Column: "loves", Kind: sqb.TypeMismatch, Detail: "column type _int4, model type []string"},
+		{Table: "tickets", Column: "status", Kind: sqb.NullabilityMismatch, Detail: "column is nullable, string cannot hold NULL"},
+		{Table: "tickets", Column: "priority", Kind: sqb.NullabilityMismatch, Detail: "column is nullable, sqb_test.examplePriority cannot hold NULL"},
+	}, drift.Mismatches)
+
+	assert.Contains(t, err.Error(), "Verify: 7 schema mismatches:\nusers.email: nullability: column is nullable, string cannot hold NULL\n")
+}
+
+func Test_Verify_PassesMatchingSchema(t *testing.T) {
+	db, _ := fakedb.New(func(query string, args []driver.Value) ([]string, [][]driver.Value, error) {
+		return []string{"table_name", "column_name", "data_type", "udt_name", "is_nullable"}, [][]driver.Value{
+			{"billing.types", "id", "uuid", "uuid", "NO"},
+			{"billing.types", "count", "integer", "int4", "NO"},
+			{"billing.types", "total", "bigint", "int8", "YES"},
+			{"billing.types", "ratio", "double precision", "float8", "NO"},
+			{"billing.types", "payload", "bytea", "bytea", "YES"},
+			{"billing.types", "labels", "ARRAY", "_text", "YES"},
+			{"billing.types", "memo", "text", "text", "YES"},
+		}, nil
+	})
+
+	type typesModel struct {
+		ID      exampleUUID     `psql:"id"`
+		Count   int             `psql:"count"`
+		Total   sqb.Null[int64] `psql:"total"`
+		Ratio   float32         `psql:"ratio"`
+		Payload []byte          `psql:"payload"`
+		Labels  []string        `psql:"labels"`
+		Memo    sql.NullString  `psql:"memo"`
+	}
+
+	tt := sqb.NewTable[any]("billing.types", sqb.Psql(), &typesModel{})
+
+	assert.NoError(t, sqb.Verify(context.Background(), sqb.NewPreparedRunner(db, 1), tt))
+}
+
+func Test_Verify_RequiresColumnsQuerier(t *testing.T) {
+	err := sqb.Verify(context.Background(), nil, sqb.NewTable[any]("users", exampleDialect{}, &exampleUserModel{}))
+	assert.EqualError(t, err, "Verify: dialect sqb_test.exampleDialect cannot describe columns, implement ColumnsQuerier")
+
+	cte := sqb.NewCTE("recent", sqb.NewTable[any]("users", sqb.Psql(), &exampleUserModel{}).Select("id"))
+	err = sqb.Verify(context.Background(), nil, sqb.FromCTE[any](cte))
+	assert.EqualError(t, err, "Verify: table recent has no dialect")
+}
+
+// The columns CreateTableSQL declares, as information_schema describes them
+func describeCreateTableSQL(tableName string, ddl string) [][]driver.Value {
+	udtNames := map[string]string{
+		"smallint": "int2", "integer": "int4", "bigint": "int8", "numeric(20)": "numeric", "real": "float4",
+		"double precision": "float8", "text": "text", "boolean": "bool", "timestamp with time zone": "timestamptz",
+		"bytea": "bytea", "jsonb": "jsonb",
+	}
+
+	rows := [][]driver.Value{}
+	for _, line := range strings.Split(ddl, "\n")[1:] {
+		line = strings.TrimSuffix(strings.TrimSpace(line), ",")
+		if line == "" || strings.HasPrefix(line, "PRIMARY KEY") || strings.HasPrefix(line, ")") {
+			continue
+		}
+
+		name, rest, _ := strings.Cut(line, " ")
+		sqlType := rest
+		for _, end := range []string{" NOT NULL", " DEFAULT", " GENERATED", " CHECK"} {
+			sqlType, _, _ = strings.Cut(sqlType, end)
+		}
+
+		nullable := "YES"
+		if strings.Contains(rest, "NOT NULL") {
+			nullable = "NO"
+		}
+
+		dataType, udtName := strings.TrimSuffix(sqlType, "(20)"), udtNames[sqlType]
+		if element, ok := strings.CutSuffix(sqlType, "[]"); ok {
+			dataType, udtName = "ARRAY", "_"+udtNames[element]
+		}
+
+		rows = append(rows, []driver.Value{tableName, name, dataType, udtName, nullable})
+	}
+
+	return rows
+}
+
+func Test_Verify_PassesCreateTableSQL(t *testing.T) {
+	type everyTypeModel struct {
+		ID       int64                       `psql:"id,pk,default"`
+		Tiny     int8                        `psql:"tiny"`
+		Small    int16                       `psql:"small"`
+		Medium   int32                       `psql:"medium"`
+		Big      int                         `psql:"big"`
+		UTiny    uint8                       `psql:"utiny"`
+		USmall   uint16                      `psql:"usmall"`
+		UMedium  uint32                      `psql:"umedium"`
+		UBig     uint64                      `psql:"ubig"`
+		Ratio    float32                     `psql:"ratio"`
+		Score    float64                     `psql:"score"`
+		Name     string                      `psql:"name"`
+		Active   bool                        `psql:"active"`
+		Created  time.Time                   `psql:"created"`
+		Payload  []byte                      `psql:"payload"`
+		Labels   []string                    `psql:"labels"`
+		Counts   []int32                     `psql:"counts"`
+		Nickname sqb.Null[string]            `psql:"nickname"`
+		Parent   *int64                      `psql:"parent"`
+		Settings sqb.JSON[map[string]string] `psql:"settings"`
+	}
+
+	tt := sqb.NewTable[any]("everything", sqb.Psql(), &everyTypeModel{})
+	rows := describeCreateTableSQL("everything", tt.CreateTableSQL(sqb.Psql()))
+	assert.Len(t, rows, len(tt.Columns()))
+
+	db, _ := fakedb.New(func(query string, args []driver.Value) ([]string, [][]driver.Value, error) {
+		return []string{"table_name", "column_name", "data_type", "udt_name", "is_nullable"}, rows, nil
+	})
+
+	assert.NoError(t, sqb.Verify(context.Background(), sqb.NewPreparedRunner(db, 1), tt))
+}
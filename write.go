@@ -0,0 +1,311 @@
+package sqb
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+/*
+	Write builders produce statements which modify a table, using the table model to decide which columns
+	are written. Values are taken from a value of the model type, so they are already type checked.
+*/
+
+type InsertBuilder[T any] struct {
+	table *Table[T]
+	row   reflect.Value
+
+	// ON CONFLICT handling, either "", "DO NOTHING", or "DO UPDATE"
+	conflict      string
+	updateColumns []string
+}
+
+// Insert a row into the table. row must be a value of, or pointer to, the table model. Readonly columns are
+// never written, and omitempty or default columns are skipped when zero.
+func (t *Table[T]) Insert(row interface{}) *InsertBuilder[T] {
+	if t.modelType == nil {
+		panic(fmt.Sprintf("Insert: table %s has no model", t.tableName))
+	}
+
+	v := reflect.Indirect(reflect.ValueOf(row))
+	if v.Type() != t.modelType {
+		panic(fmt.Sprintf("Insert: row must be a %s, got %T", t.modelType, row))
+	}
+
+	return &InsertBuilder[T]{
+		table: t,
+		row:   v,
+	}
+}
+
+// Skip the insert when it conflicts with an existing row on the primary key
+func (i *InsertBuilder[T]) OnConflictDoNothing() *InsertBuilder[T] {
+	i.assertPrimaryKey()
+	i.conflict = "DO NOTHING"
+
+	return i
+}
+
+// Update the existing row when the insert conflicts on the primary key, making the insert an upsert. When
+// no columns are given, every inserted column outside of the primary key is updated.
+func (i *InsertBuilder[T]) OnConflictUpdate(columnNames ...string) *InsertBuilder[T] {
+	i.assertPrimaryKey()
+
+	for _, columnName := range columnNames {
+		if i.table.GetColumn(columnName).readonly {
+			panic(fmt.Sprintf("OnConflictUpdate: column %s is readonly", columnName))
+		}
+	}
+
+	i.conflict = "DO UPDATE"
+	i.updateColumns = columnNames
+
+	return i
+}
+
+func (i *InsertBuilder[T]) assertPrimaryKey() {
+	if len(i.table.PrimaryKey()) == 0 {
+		panic(fmt.Sprintf("Insert: table %s has no primary key to detect conflicts on", i.table.tableName))
+	}
+}
+
+func (i *InsertBuilder[T]) Build(dialect Dialect) *Query[T] {
+	q, err := i.TryBuild(dialect)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return q
+}
+
+func (i *InsertBuilder[T]) TryBuild(dialect Dialect) (*Query[T], error) {
+	params := NewParamList(dialect)
+	columns := []string{}
+	values := []string{}
+
+	for _, columnName := range i.table.columnOrder {
+		column := i.table.fields[columnName]
+		v := i.row.FieldByIndex(column.index)
+
+		if !column.writable(v) {
+			continue
+		}
+
+		if err := column.checkEnumValue(v.Interface()); err != nil {
+			params.RecordError(err)
+		}
+
+		columns = append(columns, columnName)
+		values = append(values, params.AppendValueAndReturnParam(v.Interface()))
+	}
+
+	if len(columns) == 0 {
+		return nil, errors.New("Insert: no columns to write")
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", i.table.tableName, strings.Join(columns, ", "), strings.Join(values, ", "))
+	query += i.buildConflict(columns)
+
+	if err := params.Err(); err != nil {
+		return nil, fmt.Errorf("Build: %w", err)
+	}
+
+	return &Query[T]{
+		query:   query,
+		params:  params.GetParamList(),
+		dialect: dialect,
+
+		table:     i.table.tableName,
+		statement: "INSERT",
+	}, nil
+}
+
+func (i *InsertBuilder[T]) buildConflict(inserted []string) string {
+	if i.conflict == "" {
+		return ""
+	}
+
+	target := fmt.Sprintf(" ON CONFLICT (%s) ", strings.Join(i.table.PrimaryKey(), ", "))
+	if i.conflict == "DO NOTHING" {
+		return target + i.conflict
+	}
+
+	updateColumns := i.updateColumns
+	if len(updateColumns) == 0 {
+		for _, columnName := range inserted {
+			if !i.table.fields[columnName].primaryKey {
+				updateColumns = append(updateColumns, columnName)
+			}
+		}
+	}
+
+	// Nothing to update, but the conflict must still not fail the insert
+	if len(updateColumns) == 0 {
+		return target + "DO NOTHING"
+	}
+
+	set := make([]string, 0, len(updateColumns))
+	for _, columnName := range updateColumns {
+		set = append(set, fmt.Sprintf("%s = EXCLUDED.%s", columnName, columnName))
+	}
+
+	return target + "DO UPDATE SET " + strings.Join(set, ", ")
+}
+
+type UpdateBuilder[T any] struct {
+	table   *Table[T]
+	row     reflect.Value
+	columns []string
+}
+
+// Update the rows matching the table's filters and scopes with values from row, a value of, or pointer to,
+// the table model. When columnNames are given, exactly those columns are set, including zero values of
+// omitempty and default columns. Otherwise every column Insert would write, outside of the primary key and
+// scope columns, is set.
+//
+// The table must be filtered, or marked with Admin to update every row.
+func (t *Table[T]) Update(row interface{}, columnNames ...string) *UpdateBuilder[T] {
+	if t.modelType == nil {
+		panic(fmt.Sprintf("Update: table %s has no model", t.tableName))
+	}
+
+	v := reflect.Indirect(reflect.ValueOf(row))
+	if v.Type() != t.modelType {
+		panic(fmt.Sprintf("Update: row must be a %s, got %T", t.modelType, row))
+	}
+
+	for _, columnName := range columnNames {
+		column := t.GetColumn(columnName)
+
+		if column.readonly {
+			panic(fmt.Sprintf("Update: column %s is readonly", columnName))
+		}
+
+		if column.scope {
+			panic(fmt.Sprintf("Update: column %s is a scope column and cannot be updated", columnName))
+		}
+	}
+
+	return &UpdateBuilder[T]{
+		table:   t,
+		row:     v,
+		columns: columnNames,
+	}
+}
+
+func (u *UpdateBuilder[T]) Build(dialect Dialect) *Query[T] {
+	q, err := u.TryBuild(dialect)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return q
+}
+
+func (u *UpdateBuilder[T]) TryBuild(dialect Dialect) (*Query[T], error) {
+	params := NewParamList(dialect)
+	set := []string{}
+
+	for _, columnName := range u.updatedColumns() {
+		column := u.table.fields[columnName]
+		v := u.row.FieldByIndex(column.index)
+
+		if err := column.checkEnumValue(v.Interface()); err != nil {
+			params.RecordError(err)
+		}
+
+		set = append(set, fmt.Sprintf("%s = %s", columnName, params.AppendValueAndReturnParam(v.Interface())))
+	}
+
+	if len(set) == 0 {
+		return nil, errors.New("Update: no columns to write")
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET %s", u.table.tableName, strings.Join(set, ", "))
+	query += u.table.buildWriteWhere("Update", params)
+	params.recordUnboundParams()
+
+	if err := params.Err(); err != nil {
+		return nil, fmt.Errorf("Build: %w", err)
+	}
+
+	return &Query[T]{
+		query:   query,
+		params:  params.GetParamList(),
+		dialect: dialect,
+
+		table:     u.table.tableName,
+		statement: "UPDATE",
+	}, nil
+}
+
+func (u *UpdateBuilder[T]) updatedColumns() []string {
+	if len(u.columns) > 0 {
+		return u.columns
+	}
+
+	columns := []string{}
+	for _, columnName := range u.table.columnOrder {
+		column := u.table.fields[columnName]
+
+		if !column.primaryKey && !column.scope && column.writable(u.row.FieldByIndex(column.index)) {
+			columns = append(columns, columnName)
+		}
+	}
+
+	return columns
+}
+
+type DeleteBuilder[T any] struct {
+	table *Table[T]
+}
+
+// Delete the rows matching the table's filters and scopes. The table must be filtered, or marked with Admin
+// to delete every row.
+func (t *Table[T]) Delete() *DeleteBuilder[T] {
+	return &DeleteBuilder[T]{table: t}
+}
+
+func (d *DeleteBuilder[T]) Build(dialect Dialect) *Query[T] {
+	q, err := d.TryBuild(dialect)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return q
+}
+
+func (d *DeleteBuilder[T]) TryBuild(dialect Dialect) (*Query[T], error) {
+	params := NewParamList(dialect)
+
+	query := "DELETE FROM " + d.table.tableName + d.table.buildWriteWhere("Delete", params)
+	params.recordUnboundParams()
+
+	if err := params.Err(); err != nil {
+		return nil, fmt.Errorf("Build: %w", err)
+	}
+
+	return &Query[T]{
+		query:   query,
+		params:  params.GetParamList(),
+		dialect: dialect,
+
+		table:     d.table.tableName,
+		statement: "DELETE",
+	}, nil
+}
+
+// The WHERE clause of an update or delete. Clauses only valid in a select, and writes to every row of a
+// table not marked with Admin, are recorded as errors.
+func (t *Table[T]) buildWriteWhere(statement string, params *ParamList) string {
+	if t.filter.NumClauses() == 0 && !t.admin {
+		params.RecordError(fmt.Errorf("%s: table %s has no filters, filter the rows or use Admin to write every row", statement, t.tableName))
+	}
+
+	if len(t.joins) > 0 || len(t.ctes) > 0 || len(t.orderBy) > 0 || t.limit != nil {
+		params.RecordError(fmt.Errorf("%s: table %s has joins, CTEs, ORDER BY or LIMIT, which are only valid in a select", statement, t.tableName))
+	}
+
+	return t.buildWhere(params)
+}
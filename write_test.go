@@ -0,0 +1,155 @@
+package sqb_test
+
+import (
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	sqb "github.com/themanciraptor/SQb"
+)
+
+func Test_Insert_BuildsCorrectly(t *testing.T) {
+	type testCase struct {
+		description    string
+		build          func(tt *sqb.Table[any]) *sqb.InsertBuilder[any]
+		expectedQuery  string
+		expectedParams []interface{}
+	}
+
+	testCases := []testCase{
+		{
+			description:    "skips readonly, default and empty omitempty columns",
+			build:          func(tt *sqb.Table[any]) *sqb.InsertBuilder[any] { return tt.Insert(exampleUserModel{Email: "a@b.c"}) },
+			expectedQuery:  "INSERT INTO users (email) VALUES ($1)",
+			expectedParams: []interface{}{"a@b.c"},
+		},
+		{
+			description: "writes default and omitempty columns when set",
+			build: func(tt *sqb.Table[any]) *sqb.InsertBuilder[any] {
+				return tt.Insert(&exampleUserModel{ID: 3, Email: "a@b.c", Nickname: "a"})
+			},
+			expectedQuery:  "INSERT INTO users (id, email, nickname) VALUES ($1, $2, $3)",
+			expectedParams: []interface{}{int64(3), "a@b.c", "a"},
+		},
+		{
+			description: "binds embedded and inline struct fields",
+			build: func(tt *sqb.Table[any]) *sqb.InsertBuilder[any] {
+				return sqb.NewTable[any]("customers", sqb.Psql(), &exampleCustomerModel{}).Insert(exampleCustomerModel{
+					exampleTimestamps: exampleTimestamps{UpdatedAt: time.Unix(0, 0).UTC()},
+					Address:           exampleAddress{Street: "Rue", City: "Paris"},
+				})
+			},
+			expectedQuery:  "INSERT INTO customers (updated_at, addr_street, addr_city) VALUES ($1, $2, $3)",
+			expectedParams: []interface{}{time.Unix(0, 0).UTC(), "Rue", "Paris"},
+		},
+		{
+			description: "upserts on the primary key",
+			build: func(tt *sqb.Table[any]) *sqb.InsertBuilder[any] {
+				return tt.Insert(exampleUserModel{ID: 3, Email: "a@b.c", Nickname: "a"}).OnConflictUpdate()
+			},
+			expectedQuery:  "INSERT INTO users (id, email, nickname) VALUES ($1, $2, $3) ON CONFLICT (id) DO UPDATE SET email = EXCLUDED.email, nickname = EXCLUDED.nickname",
+			expectedParams: []interface{}{int64(3), "a@b.c", "a"},
+		},
+		{
+			description: "upserts selected columns",
+			build: func(tt *sqb.Table[any]) *sqb.InsertBuilder[any] {
+				return tt.Insert(exampleUserModel{ID: 3, Email: "a@b.c"}).OnConflictUpdate("email")
+			},
+			expectedQuery:  "INSERT INTO users (id, email) VALUES ($1, $2) ON CONFLICT (id) DO UPDATE SET email = EXCLUDED.email",
+			expectedParams: []interface{}{int64(3), "a@b.c"},
+		},
+		{
+			description: "ignores conflicts",
+			build: func(tt *sqb.Table[any]) *sqb.InsertBuilder[any] {
+				return tt.Insert(exampleUserModel{ID: 3, Email: "a@b.c"}).OnConflictDoNothing()
+			},
+			expectedQuery:  "INSERT INTO users (id, email) VALUES ($1, $2) ON CONFLICT (id) DO NOTHING",
+			expectedParams: []interface{}{int64(3), "a@b.c"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			tt := sqb.NewTable[any]("users", sqb.Psql(), &exampleUserModel{})
+
+			q := tc.build(tt).Build(sqb.Psql())
+
+			assert.Equal(t, tc.expectedQuery, q.GetQuery())
+			assert.Equal(t, tc.expectedParams, q.GetParams())
+		})
+	}
+}
+
+func Test_Insert_Errors(t *testing.T) {
+	users := sqb.NewTable[any]("users", sqb.Psql(), &exampleUserModel{})
+
+	assert.PanicsWithValue(t, "Insert: row must be a sqb_test.exampleUserModel, got sqb_test.exampleModel", func() {
+		users.Insert(exampleModel{})
+	})
+
+	assert.PanicsWithValue(t, "Insert: table exampleTable has no primary key to detect conflicts on", func() {
+		sqb.NewTable[any]("exampleTable", sqb.Psql(), &exampleModel{}).Insert(exampleModel{}).OnConflictUpdate()
+	})
+
+	assert.PanicsWithValue(t, "OnConflictUpdate: column created_at is readonly", func() {
+		users.Insert(exampleUserModel{}).OnConflictUpdate("created_at")
+	})
+
+	_, err := sqb.NewTable[any]("tickets", sqb.Psql(), &exampleTicketModel{}).
+		Insert(exampleTicketModel{Status: "archived", Priority: "low"}).
+		TryBuild(sqb.Psql())
+	assert.EqualError(t, err, `Build: column status: value "archived" is not one of open|closed|pending`)
+}
+
+func Test_Write_BindsSlicesAsArrays(t *testing.T) {
+	tt := sqb.NewTable[any]("exampleTable", sqb.Psql(), &exampleModel{})
+
+	insert := tt.Insert(exampleModel{Name: "a", Loves: []string{"doom", "gloom"}}).Build(sqb.Psql())
+	update := tt.ColumnEquals("cool", "a").Update(exampleModel{Loves: []string{"doom", "gloom"}}, "loves").Build(sqb.Psql())
+
+	for _, params := range [][]interface{}{insert.GetParams(), update.GetParams()[:1]} {
+		loves := params[len(params)-1]
+		if !assert.Implements(t, (*driver.Valuer)(nil), loves) {
+			continue
+		}
+
+		v, err := loves.(driver.Valuer).Value()
+		assert.NoError(t, err)
+		assert.Equal(t, "{\"doom\",\"gloom\"}", v)
+	}
+
+	assert.NotContains(t, insert.DebugString(), "unsupported type")
+}
+
+func Test_UpdateAndDelete_BuildCorrectly(t *testing.T) {
+	users := func() *sqb.Table[any] { return sqb.NewTable[any]("users", sqb.Psql(), &exampleUserModel{}) }
+
+	q := users().GetByID(int64(3)).Update(exampleUserModel{Email: "a@b.c"}).Build(sqb.Psql())
+	assert.Equal(t, "UPDATE users SET email = $1 WHERE id = $2", q.GetQuery())
+	assert.Equal(t, []interface{}{"a@b.c", int64(3)}, q.GetParams())
+
+	q = users().ColumnEquals("email", "a@b.c").Update(exampleUserModel{}, "nickname").Build(sqb.Psql())
+	assert.Equal(t, "UPDATE users SET nickname = $1 WHERE email = $2", q.GetQuery())
+	assert.Equal(t, []interface{}{"", "a@b.c"}, q.GetParams())
+
+	q = users().GetByID(int64(3)).Delete().Build(sqb.Psql())
+	assert.Equal(t, "DELETE FROM users WHERE id = $1", q.GetQuery())
+	assert.Equal(t, []interface{}{int64(3)}, q.GetParams())
+
+	assert.PanicsWithValue(t, "Update: column created_at is readonly", func() {
+		users().Update(exampleUserModel{}, "created_at")
+	})
+
+	_, err := users().Update(exampleUserModel{Email: "a@b.c"}).TryBuild(sqb.Psql())
+	assert.EqualError(t, err, "Build: Update: table users has no filters, filter the rows or use Admin to write every row")
+
+	_, err = users().Delete().TryBuild(sqb.Psql())
+	assert.EqualError(t, err, "Build: Delete: table users has no filters, filter the rows or use Admin to write every row")
+
+	q = users().Admin().Delete().Build(sqb.Psql())
+	assert.Equal(t, "DELETE FROM users", q.GetQuery())
+
+	_, err = users().GetByID(int64(3)).Limit(1, 0).Delete().TryBuild(sqb.Psql())
+	assert.EqualError(t, err, "Build: Delete: table users has joins, CTEs, ORDER BY or LIMIT, which are only valid in a select")
+}